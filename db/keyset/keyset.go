@@ -0,0 +1,141 @@
+package keyset
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidCursor = fmt.Errorf("invalid cursor")
+)
+
+// Cursor the position of a page in a keyset pagination, the sort key values of its first or last row
+type Cursor struct {
+	// Keys the sort keys the values were read for, descending ones prefixed by "-"
+	Keys   []string
+	Values []any
+	// Backward the page before the row, for a previous page
+	Backward bool
+}
+
+// Key the cursor key of a sort key
+func Key(name string, desc bool) string {
+	if desc {
+		return "-" + name
+	}
+
+	return name
+}
+
+// Codec turns cursors into opaque tokens signed with HMAC-SHA256, so a client can't forge a position
+// it isn't allowed to see, and back
+type Codec struct {
+	key []byte
+}
+
+// NewCodec key is the secret the tokens are signed with, at least 32 random bytes
+func NewCodec(key []byte) *Codec {
+	return &Codec{
+		key: key,
+	}
+}
+
+type token struct {
+	Keys     []string `bson:"k"`
+	Values   bson.A   `bson:"v"`
+	Backward bool     `bson:"b"`
+}
+
+const (
+	// timeKey wraps a time.Time in the token, a bson datetime would cut it to milliseconds
+	timeKey string = "$t"
+)
+
+func (c *Codec) Encode(cursor Cursor) (string, error) {
+	values := make(bson.A, 0, len(cursor.Values))
+	for _, v := range cursor.Values {
+		if t, ok := v.(time.Time); ok {
+			v = bson.D{{Key: timeKey, Value: t.Format(time.RFC3339Nano)}}
+		}
+		values = append(values, v)
+	}
+
+	payload, err := bson.Marshal(token{
+		Keys:     cursor.Keys,
+		Values:   values,
+		Backward: cursor.Backward,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(append(payload, c.sign(payload)...)), nil
+}
+
+// Decode keys are the sort keys of the query, a token made for other keys is invalid
+func (c *Codec) Decode(s string, keys []string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(raw) < sha256.Size {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	payload, mac := raw[:len(raw)-sha256.Size], raw[len(raw)-sha256.Size:]
+	if !hmac.Equal(mac, c.sign(payload)) {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	t := token{}
+	if err := bson.Unmarshal(payload, &t); err != nil {
+		return Cursor{}, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	if !slices.Equal(t.Keys, keys) || len(t.Values) != len(keys) {
+		return Cursor{}, fmt.Errorf("%w: made for another sort", ErrInvalidCursor)
+	}
+
+	values := make([]any, 0, len(t.Values))
+	for _, v := range t.Values {
+		if d, ok := v.(bson.D); ok && len(d) == 1 && d[0].Key == timeKey {
+			s, _ := d[0].Value.(string)
+			tv, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return Cursor{}, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+			}
+			v = tv
+		}
+		// a []byte comes back as generic binary, which database/sql doesn't take as a parameter
+		if b, ok := v.(primitive.Binary); ok && b.Subtype == bson.TypeBinaryGeneric {
+			v = b.Data
+		}
+		values = append(values, v)
+	}
+
+	return Cursor{
+		Keys:     t.Keys,
+		Values:   values,
+		Backward: t.Backward,
+	}, nil
+}
+
+func (c *Codec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// Links whether a page has a next and a previous page, cursor is the one it was read after, nil for the first page,
+// and fetched the rows read with a limit of size+1
+func Links(cursor *Cursor, fetched int, size int) (bool, bool) {
+	more := fetched > size
+	if cursor != nil && cursor.Backward {
+		return true, more
+	}
+
+	return more, cursor != nil
+}
@@ -0,0 +1,73 @@
+package keyset
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	codec := NewCodec([]byte("0123456789abcdef0123456789abcdef"))
+	at := time.Date(2024, 2, 29, 12, 30, 45, 123456789, time.UTC)
+
+	tests := []struct {
+		name   string
+		cursor Cursor
+		// want the decoded values, an int comes back as the int32 or int64 bson stores it as
+		want []any
+	}{
+		{"int", Cursor{Keys: []string{"id"}, Values: []any{int64(42)}, Backward: false}, []any{int64(42)}},
+		{"string", Cursor{Keys: []string{"-name", "id"}, Values: []any{"b", int64(7)}, Backward: true}, []any{"b", int64(7)}},
+		{"time", Cursor{Keys: []string{"-created_at", "id"}, Values: []any{at, int64(7)}, Backward: false}, []any{at, int64(7)}},
+		{"bytes", Cursor{Keys: []string{"id"}, Values: []any{[]byte{0x01, 0x8f, 0x00, 0xff}}, Backward: false}, []any{[]byte{0x01, 0x8f, 0x00, 0xff}}},
+		{"nil", Cursor{Keys: []string{"deleted_at", "id"}, Values: []any{nil, int64(7)}, Backward: false}, []any{nil, int64(7)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := codec.Encode(tt.cursor)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := codec.Decode(token, tt.cursor.Keys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Backward != tt.cursor.Backward || !reflect.DeepEqual(got.Values, tt.want) {
+				t.Fatalf("got %#v backward %v, want %#v backward %v", got.Values, got.Backward, tt.want, tt.cursor.Backward)
+			}
+		})
+	}
+}
+
+func TestCodecInvalid(t *testing.T) {
+	codec := NewCodec([]byte("0123456789abcdef0123456789abcdef"))
+
+	token, err := codec.Encode(Cursor{Keys: []string{"id"}, Values: []any{int64(1)}, Backward: false})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		keys  []string
+	}{
+		{"other sort", token, []string{"-id"}},
+		{"other key", token, []string{"id", "name"}},
+		{"tampered", token[:len(token)-2] + "AA", []string{"id"}},
+		{"other codec", func() string {
+			other, _ := NewCodec([]byte("another secret of 32 bytes......")).Encode(Cursor{Keys: []string{"id"}, Values: []any{int64(1)}, Backward: false})
+			return other
+		}(), []string{"id"}},
+		{"not base64", "!!", []string{"id"}},
+	}
+
+	for _, tt := range tests {
+		if _, err := codec.Decode(tt.token, tt.keys); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, ErrInvalidCursor)
+		}
+	}
+}
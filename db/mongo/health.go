@@ -0,0 +1,118 @@
+package mongo
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/Lee-Chi/go-sdk/internal/backoff"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	healthTimeout     time.Duration = 5 * time.Second
+	maxConnectBackoff time.Duration = 30 * time.Second
+)
+
+type Option func(o *databaseOptions)
+
+type databaseOptions struct {
+	maxWait  time.Duration
+	backoff  time.Duration
+	queryLog QueryLog
+}
+
+// WithConnectRetry NewDatabase pings the deployment again until it answers or maxWait has passed, for a deployment
+// which starts along with the service. Pings are backoff apart at first, twice as far apart each time up to 30 seconds.
+// The driver reconnects by itself afterwards.
+func WithConnectRetry(maxWait time.Duration, backoff time.Duration) Option {
+	return func(o *databaseOptions) {
+		o.maxWait = maxWait
+		o.backoff = backoff
+	}
+}
+
+func pingWithRetry(ctx context.Context, client *mongo.Client, o databaseOptions) error {
+	if o.maxWait <= 0 || o.backoff <= 0 {
+		return client.Ping(ctx, nil)
+	}
+
+	// the server selection of the driver waits for the server as well, until maxWait at most
+	ctx, cancel := context.WithTimeout(ctx, o.maxWait)
+	defer cancel()
+
+	b := backoff.New(o.backoff, maxConnectBackoff)
+	for {
+		err := client.Ping(ctx, nil)
+		if err == nil || !b.Wait(ctx) {
+			return err
+		}
+	}
+}
+
+// PoolStats the connections of the pools of every server the client is connected to
+type PoolStats struct {
+	OpenConnections int64
+	InUse           int64
+	Idle            int64
+	// WaitFailed checkouts which failed, as on a timeout waiting for a free connection
+	WaitFailed int64
+}
+
+type poolStats struct {
+	open       atomic.Int64
+	inUse      atomic.Int64
+	waitFailed atomic.Int64
+}
+
+func (p *poolStats) monitor() *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(e *event.PoolEvent) {
+			switch e.Type {
+			case event.ConnectionCreated:
+				p.open.Add(1)
+			case event.ConnectionClosed:
+				p.open.Add(-1)
+			case event.GetSucceeded:
+				p.inUse.Add(1)
+			case event.ConnectionReturned:
+				p.inUse.Add(-1)
+			case event.GetFailed:
+				p.waitFailed.Add(1)
+			}
+		},
+	}
+}
+
+func (p *poolStats) stats() PoolStats {
+	open, inUse := p.open.Load(), p.inUse.Load()
+
+	return PoolStats{
+		OpenConnections: open,
+		InUse:           inUse,
+		Idle:            max(open-inUse, 0),
+		WaitFailed:      p.waitFailed.Load(),
+	}
+}
+
+// Health of the deployment for readiness probes
+type Health struct {
+	// Latency of the ping
+	Latency time.Duration
+	Pool    PoolStats
+}
+
+// Health pings the primary of the deployment and reports the pools of the client, giving up after 5 seconds
+func (db *Database) Health(ctx context.Context) (Health, error) {
+	ctx, cancel := context.WithTimeout(ctx, healthTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := db.Database.Client().Ping(ctx, nil)
+
+	return Health{
+		Latency: time.Since(start),
+		Pool:    db.pool.stats(),
+	}, err
+}
@@ -0,0 +1,177 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/Lee-Chi/go-sdk/db/keyset"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Page the cursors of the pages around a page of Paginate, empty at the ends
+type Page struct {
+	Next string
+	Prev string
+}
+
+// Paginate reads the page of size documents after the cursor into results, the first page if cursor is empty.
+// Documents are ordered by the Sort of the collection followed by _id as a tie-breaker, Skip and Limit are ignored.
+// Unlike Skip it stays fast deep into a large collection as long as an index covers the sort keys,
+// which must be set on every document and kept by the Projection.
+func (c *Collection) Paginate(ctx context.Context, codec *keyset.Codec, cursor string, size int64, results any) (Page, error) {
+	sort := append(S{}, c.sort...)
+	if !slices.ContainsFunc(sort, func(e bson.E) bool { return e.Key == string(Key_ID) }) {
+		sort = append(sort, Key_ID.Asc()...)
+	}
+
+	keys := make([]string, 0, len(sort))
+	for _, e := range sort {
+		keys = append(keys, keyset.Key(e.Key, descending(e.Value)))
+	}
+
+	var after *keyset.Cursor
+	if cursor != "" {
+		cur, err := codec.Decode(cursor, keys)
+		if err != nil {
+			return Page{}, err
+		}
+		after = &cur
+	}
+
+	backward := after != nil && after.Backward
+	filter := c.filter
+	if after != nil {
+		filter = filter.And(keysetAfter(sort, after.Values, backward))
+	}
+
+	order := make(S, 0, len(sort))
+	for _, e := range sort {
+		dir := 1
+		if descending(e.Value) != backward {
+			dir = -1
+		}
+		order = append(order, bson.E{Key: e.Key, Value: dir})
+	}
+
+	opts := options.Find().SetSort(order).SetLimit(size + 1)
+	if len(c.proj) > 0 {
+		opts.SetProjection(c.proj)
+	}
+
+	found, err := c.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return Page{}, err
+	}
+
+	docs := []bson.Raw{}
+	if err := found.All(ctx, &docs); err != nil {
+		return Page{}, err
+	}
+
+	hasNext, hasPrev := keyset.Links(after, len(docs), int(size))
+	docs = docs[:min(int64(len(docs)), size)]
+	if backward {
+		slices.Reverse(docs)
+	}
+
+	if err := decodeAll(docs, results); err != nil {
+		return Page{}, err
+	}
+
+	page := Page{
+		Next: "",
+		Prev: "",
+	}
+	if len(docs) == 0 {
+		return page, nil
+	}
+
+	if hasNext {
+		if page.Next, err = keysetCursor(docs[len(docs)-1], sort, keys, false, codec); err != nil {
+			return Page{}, err
+		}
+	}
+	if hasPrev {
+		if page.Prev, err = keysetCursor(docs[0], sort, keys, true, codec); err != nil {
+			return Page{}, err
+		}
+	}
+
+	return page, nil
+}
+
+// descending the direction of a sort value, -1 of Desc
+func descending(v any) bool {
+	switch n := v.(type) {
+	case int:
+		return n < 0
+	case int32:
+		return n < 0
+	case int64:
+		return n < 0
+	case float64:
+		return n < 0
+	}
+
+	return false
+}
+
+// keysetAfter {$or: [{a: {$gt: ?}}, {a: ?, b: {$gt: ?}} ...]} for the sort keys a, b ..., with $lt for the descending ones
+func keysetAfter(sort S, values []any, backward bool) F {
+	ors := make([]F, 0, len(sort))
+	for i, e := range sort {
+		f := make(F, 0, i+1)
+		for j := 0; j < i; j++ {
+			f = append(f, bson.E{Key: sort[j].Key, Value: values[j]})
+		}
+
+		op := "$gt"
+		if descending(e.Value) != backward {
+			op = "$lt"
+		}
+		f = append(f, bson.E{Key: e.Key, Value: F{{Key: op, Value: values[i]}}})
+
+		ors = append(ors, f)
+	}
+
+	return F{bson.E{Key: "$or", Value: ors}}
+}
+
+func keysetCursor(doc bson.Raw, sort S, keys []string, backward bool, codec *keyset.Codec) (string, error) {
+	values := make([]any, 0, len(sort))
+	for _, e := range sort {
+		v, err := doc.LookupErr(strings.Split(e.Key, ".")...)
+		if err != nil {
+			return "", fmt.Errorf("sort key %s is not in the document: %w", e.Key, err)
+		}
+		values = append(values, v)
+	}
+
+	return codec.Encode(keyset.Cursor{
+		Keys:     keys,
+		Values:   values,
+		Backward: backward,
+	})
+}
+
+// decodeAll decodes docs into results, a pointer to a slice as for Find
+func decodeAll(docs []bson.Raw, results any) error {
+	rv := reflect.ValueOf(results)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("results must be a pointer to a slice, not %T", results)
+	}
+
+	slice := reflect.MakeSlice(rv.Elem().Type(), len(docs), len(docs))
+	for i, doc := range docs {
+		if err := bson.Unmarshal(doc, slice.Index(i).Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	rv.Elem().Set(slice)
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package mongo
+
+import (
+	"context"
+	"path"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Lee-Chi/go-sdk/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// QueryLog how the commands are written to the logger package, failed ones as errors and slow ones as warnings
+type QueryLog struct {
+	// Slow threshold of a slow command, 200ms if zero
+	Slow time.Duration
+	// All every command at debug level
+	All bool
+	// Redact replaces the values of the logged commands by "?", keeping their fields and operators
+	Redact bool
+}
+
+const (
+	defaultSlowQuery time.Duration = 200 * time.Millisecond
+	// maxKeptCommand the larger commands are only kept whole until they finish when every command is logged
+	maxKeptCommand int = 1024
+)
+
+// WithQueryLog replaces the default QueryLog, which logs the failed and slow commands
+func WithQueryLog(q QueryLog) Option {
+	return func(o *databaseOptions) {
+		o.queryLog = q
+	}
+}
+
+// callerPrefixes the frames of the driver and this module's db packages are skipped for the caller of a command
+var callerPrefixes = []string{
+	"go.mongodb.org/",
+	path.Dir(reflect.TypeOf(Database{}).PkgPath()) + "/",
+}
+
+// commandFields of the driver which say nothing about the command
+var commandFields = map[string]bool{
+	"lsid":             true,
+	"$clusterTime":     true,
+	"$db":              true,
+	"$readPreference":  true,
+	"txnNumber":        true,
+	"autocommit":       true,
+	"startTransaction": true,
+}
+
+type commandLogger struct {
+	config QueryLog
+	// started the commands by request id, they are only in the started event and formatted once logged
+	started sync.Map
+}
+
+func newCommandLogger(config QueryLog) *commandLogger {
+	if config.Slow <= 0 {
+		config.Slow = defaultSlowQuery
+	}
+
+	return &commandLogger{
+		config:  config,
+		started: sync.Map{},
+	}
+}
+
+func (l *commandLogger) monitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			l.started.Store(e.RequestID, l.start(e))
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			command, _ := l.started.LoadAndDelete(e.RequestID)
+
+			var level, reason string
+			switch {
+			case e.Duration > l.config.Slow:
+				level, reason = logger.Level_Warn, "slow command"
+			case l.config.All:
+				level, reason = logger.Level_Debug, "command"
+			default:
+				return
+			}
+
+			l.log(level, reason, e.Duration, affected(e.Reply), command)
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			command, _ := l.started.LoadAndDelete(e.RequestID)
+
+			l.log(logger.Level_Error, e.Failure, e.Duration, "-", command)
+		},
+	}
+}
+
+type startedCommand struct {
+	name       string
+	collection string
+	// command nil if it was too large to keep, only its name, collection and size are logged then
+	command bson.Raw
+	size    int
+}
+
+// start what a slow or failed command is logged with, the command of the event is a copy made by the driver which
+// would otherwise stay in memory until the command finishes, as the documents of a large insert
+func (l *commandLogger) start(e *event.CommandStartedEvent) startedCommand {
+	s := startedCommand{
+		name:       e.CommandName,
+		collection: "",
+		command:    nil,
+		size:       len(e.Command),
+	}
+
+	if l.config.All || len(e.Command) <= maxKeptCommand {
+		s.command = e.Command
+	} else {
+		s.collection, _ = e.Command.Lookup(e.CommandName).StringValueOK()
+	}
+
+	return s
+}
+
+func (l *commandLogger) log(level string, reason string, elapsed time.Duration, rows string, started any) {
+	command := ""
+	if s, ok := started.(startedCommand); ok {
+		switch {
+		case s.command != nil:
+			command = l.format(s.name, s.command)
+		case s.size > 0:
+			command = s.name + " " + s.collection + " (" + strconv.Itoa(s.size) + " bytes)"
+		default:
+			// the driver leaves out the commands of the authentication
+			command = s.name
+		}
+	}
+
+	logger.Log(level, logger.CallerOutside(callerPrefixes...), "%s | %.3fms | rows:%s | %s",
+		reason, float64(elapsed.Microseconds())/1000, rows, command)
+}
+
+// format the command as extended JSON without the fields of the driver, redacted if configured
+func (l *commandLogger) format(name string, command bson.Raw) string {
+	doc := bson.D{}
+	if err := bson.Unmarshal(command, &doc); err != nil {
+		return name
+	}
+
+	fields := make(bson.D, 0, len(doc))
+	for i, e := range doc {
+		if commandFields[e.Key] {
+			continue
+		}
+		// the first field is the command and its collection, which is kept
+		if l.config.Redact && i > 0 {
+			e.Value = redact(e.Value)
+		}
+		fields = append(fields, e)
+	}
+
+	text, err := bson.MarshalExtJSON(fields, false, false)
+	if err != nil {
+		return name
+	}
+
+	return string(text)
+}
+
+// redact replaces every value which is not a document or an array by "?"
+func redact(v any) any {
+	switch v := v.(type) {
+	case bson.D:
+		d := make(bson.D, 0, len(v))
+		for _, e := range v {
+			d = append(d, bson.E{Key: e.Key, Value: redact(e.Value)})
+		}
+		return d
+	case bson.A:
+		a := make(bson.A, 0, len(v))
+		for _, e := range v {
+			a = append(a, redact(e))
+		}
+		return a
+	default:
+		return "?"
+	}
+}
+
+// affected n of the writes or the documents of the first batch of a cursor, "-" for other commands
+func affected(reply bson.Raw) string {
+	if n, ok := reply.Lookup("n").AsInt64OK(); ok {
+		return strconv.FormatInt(n, 10)
+	}
+
+	for _, batch := range []string{"firstBatch", "nextBatch"} {
+		if docs, ok := reply.Lookup("cursor", batch).ArrayOK(); ok {
+			values, _ := docs.Values()
+			return strconv.Itoa(len(values))
+		}
+	}
+
+	return "-"
+}
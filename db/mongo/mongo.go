@@ -11,6 +11,8 @@ import (
 
 type Database struct {
 	*mongo.Database
+
+	pool *poolStats
 }
 
 type Collection struct {
@@ -23,18 +25,38 @@ type Collection struct {
 	limit  int64
 }
 
-func NewDatabase(ctx context.Context, uri string, name string) (*Database, error) {
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+func NewDatabase(ctx context.Context, uri string, name string, opts ...Option) (*Database, error) {
+	o := databaseOptions{
+		maxWait: 0,
+		backoff: 0,
+		queryLog: QueryLog{
+			Slow:   defaultSlowQuery,
+			All:    false,
+			Redact: false,
+		},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	pool := &poolStats{}
+	client, err := mongo.Connect(ctx, options.Client().
+		ApplyURI(uri).
+		SetPoolMonitor(pool.monitor()).
+		SetMonitor(newCommandLogger(o.queryLog).monitor()))
 	if err != nil {
 		return nil, err
 	}
 
-	if err := client.Ping(ctx, nil); err != nil {
+	if err := pingWithRetry(ctx, client, o); err != nil {
+		client.Disconnect(context.Background())
 		return nil, err
 	}
 
 	return &Database{
 		Database: client.Database(name),
+
+		pool: pool,
 	}, nil
 }
 
@@ -0,0 +1,208 @@
+package sql
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	gomysql "github.com/go-sql-driver/mysql"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/driver/sqlserver"
+	"gorm.io/gorm"
+)
+
+// Driver builds the DSN of a database server and opens it with gorm
+type Driver interface {
+	Name() string
+	DSN(db *Database) string
+	Dialector(dsn string) gorm.Dialector
+}
+
+func WithDriver(driver Driver) Option {
+	return func(db *Database) {
+		db.driver = driver
+	}
+}
+
+const (
+	DriverName_MySQL     string = "mysql"
+	DriverName_Postgres  string = "postgres"
+	DriverName_SQLite    string = "sqlite"
+	DriverName_SQLServer string = "sqlserver"
+)
+
+// MySQL the default driver, zero values keep the defaults of the previous fixed DSN
+type MySQL struct {
+	Charset   string
+	Collation string
+	// Location of DATETIME values, time.Local if nil, use time.UTC when the server stores UTC
+	Location *time.Location
+	// TLS nil for a plain connection
+	TLS          *tls.Config
+	Timeout      time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	// Params any other parameter of github.com/go-sql-driver/mysql, as system variables like time_zone
+	Params map[string]string
+}
+
+func (d MySQL) Name() string {
+	return DriverName_MySQL
+}
+
+func (d MySQL) DSN(db *Database) string {
+	cfg := gomysql.NewConfig()
+	cfg.User = db.user
+	cfg.Passwd = db.password
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(db.ip, db.port)
+	cfg.DBName = db.dbName
+	cfg.ParseTime = true
+
+	cfg.Loc = time.Local
+	if d.Location != nil {
+		cfg.Loc = d.Location
+	}
+
+	cfg.Params = map[string]string{
+		"charset": "utf8mb4",
+	}
+	if d.Charset != "" {
+		cfg.Params["charset"] = d.Charset
+	}
+	for k, v := range d.Params {
+		cfg.Params[k] = v
+	}
+
+	if d.Collation != "" {
+		cfg.Collation = d.Collation
+	}
+
+	if d.TLS != nil {
+		// a *tls.Config can't be written in a DSN, it is referred to by a registered name
+		name := fmt.Sprintf("go-sdk-%p", d.TLS)
+		gomysql.RegisterTLSConfig(name, d.TLS)
+		cfg.TLSConfig = name
+	}
+
+	cfg.Timeout = d.Timeout
+	cfg.ReadTimeout = d.ReadTimeout
+	cfg.WriteTimeout = d.WriteTimeout
+
+	return cfg.FormatDSN()
+}
+
+func (d MySQL) Dialector(dsn string) gorm.Dialector {
+	return mysql.Open(dsn)
+}
+
+type Postgres struct {
+	// SSLMode disable, require, verify-ca or verify-full, disable if empty
+	SSLMode  string
+	TimeZone string
+	// Params any other parameter of github.com/jackc/pgx, as connect_timeout or application_name
+	Params map[string]string
+}
+
+func (d Postgres) Name() string {
+	return DriverName_Postgres
+}
+
+func (d Postgres) DSN(db *Database) string {
+	sslMode := d.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(db.user, db.password),
+		Host:   net.JoinHostPort(db.ip, db.port),
+		Path:   "/" + db.dbName,
+	}
+
+	query := url.Values{}
+	query.Set("sslmode", sslMode)
+	if d.TimeZone != "" {
+		query.Set("TimeZone", d.TimeZone)
+	}
+	for k, v := range d.Params {
+		query.Set(k, v)
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+func (d Postgres) Dialector(dsn string) gorm.Dialector {
+	return postgres.Open(dsn)
+}
+
+// SQLite the database name is the file path, use Memory for an in-memory database
+type SQLite struct{}
+
+const (
+	// Memory an in-memory SQLite database shared by the connections of one Database
+	Memory string = ":memory:"
+)
+
+func (d SQLite) Name() string {
+	return DriverName_SQLite
+}
+
+func (d SQLite) DSN(db *Database) string {
+	if db.dbName == Memory || db.dbName == "" {
+		// every connection to ":memory:" gets its own database, a shared cache keeps them on one
+		return fmt.Sprintf("file:%p?mode=memory&cache=shared&_foreign_keys=on", db)
+	}
+
+	return fmt.Sprintf("file:%s?_foreign_keys=on", db.dbName)
+}
+
+func (d SQLite) Dialector(dsn string) gorm.Dialector {
+	return sqlite.Open(dsn)
+}
+
+type SQLServer struct {
+	// Encrypt disable, false, true or strict, the driver default if empty
+	Encrypt string
+	// Params any other parameter of github.com/microsoft/go-mssqldb, as "connection timeout"
+	Params map[string]string
+}
+
+func (d SQLServer) Name() string {
+	return DriverName_SQLServer
+}
+
+func (d SQLServer) DSN(db *Database) string {
+	u := url.URL{
+		Scheme: "sqlserver",
+		User:   url.UserPassword(db.user, db.password),
+		Host:   net.JoinHostPort(db.ip, db.port),
+	}
+
+	query := url.Values{}
+	query.Set("database", db.dbName)
+	if d.Encrypt != "" {
+		query.Set("encrypt", d.Encrypt)
+	}
+	for k, v := range d.Params {
+		query.Set(k, v)
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+func (d SQLServer) Dialector(dsn string) gorm.Dialector {
+	return sqlserver.Open(dsn)
+}
+
+// NewSQLite path is the database file or Memory
+func NewSQLite(path string, opts ...Option) *Database {
+	return New("", "", path, "", "", append([]Option{WithDriver(SQLite{})}, opts...)...)
+}
@@ -0,0 +1,117 @@
+package sql
+
+import (
+	"context"
+	stdsql "database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Lee-Chi/go-sdk/internal/backoff"
+)
+
+var (
+	ErrNotOpen = fmt.Errorf("database is not open")
+)
+
+const (
+	healthTimeout  time.Duration = 5 * time.Second
+	maxOpenBackoff time.Duration = 30 * time.Second
+)
+
+// WithOpenRetry for a server which starts along with the service, Open retries until maxWait has passed and gives
+// each attempt only the time left. The wait between attempts starts at backoff and doubles up to 30 seconds.
+// Once open, the pool replaces broken connections by itself.
+func WithOpenRetry(maxWait time.Duration, backoff time.Duration) Option {
+	return func(db *Database) {
+		db.openRetry.maxWait = maxWait
+		db.openRetry.backoff = backoff
+	}
+}
+
+type openRetry struct {
+	maxWait time.Duration
+	backoff time.Duration
+}
+
+func (db *Database) openWithRetry() error {
+	if db.openRetry.maxWait <= 0 || db.openRetry.backoff <= 0 {
+		return db.open()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), db.openRetry.maxWait)
+	defer cancel()
+
+	b := backoff.New(db.openRetry.backoff, maxOpenBackoff)
+	var last error
+	for {
+		err := db.openContext(ctx)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil && last != nil {
+			// the attempt which ran out of time says less than the one before
+			return last
+		}
+		if !b.Wait(ctx) {
+			return err
+		}
+		last = err
+	}
+}
+
+// openContext gives up on open once ctx ends, the attempt opens a copy of db which is kept only if it succeeds in
+// time and closed otherwise
+func (db *Database) openContext(ctx context.Context) error {
+	attempt := *db
+	done := make(chan error, 1)
+	go func() {
+		done <- attempt.open()
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			db.DB = attempt.DB
+			db.resolver = attempt.resolver
+		}
+		return err
+	case <-ctx.Done():
+		go func() {
+			if err := <-done; err == nil {
+				attempt.Close()
+			}
+		}()
+		return fmt.Errorf("%w: %w", ErrNotOpen, ctx.Err())
+	}
+}
+
+// Health of the primary for readiness probes
+type Health struct {
+	// Latency of the ping
+	Latency time.Duration
+	Stats   stdsql.DBStats
+}
+
+// Health pings the primary through the pool and reports its statistics, ErrNotOpen before Open.
+// The ping is given 5 seconds at most.
+func (db *Database) Health(ctx context.Context) (Health, error) {
+	if db.DB == nil {
+		return Health{}, ErrNotOpen
+	}
+
+	sqlDB, err := db.DB.DB()
+	if err != nil {
+		return Health{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err = sqlDB.PingContext(ctx)
+
+	return Health{
+		Latency: time.Since(start),
+		Stats:   sqlDB.Stats(),
+	}, err
+}
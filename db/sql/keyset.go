@@ -0,0 +1,160 @@
+package sql
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/Lee-Chi/go-sdk/db/keyset"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Page a page of a keyset pagination, Next and Prev are the cursors of the pages around it, empty at the ends
+type Page[T any] struct {
+	Items []T
+	Next  string
+	Prev  string
+}
+
+// Paginate reads the page of size rows after the cursor, the first page if cursor is empty.
+// Rows are ordered by the sorts of q followed by the primary key as a tie-breaker, its offset and limit are ignored.
+// Unlike an offset it stays fast deep into a large table as long as an index covers the sort columns,
+// which must not be NULL.
+func (r *Repository[T]) Paginate(ctx context.Context, q *Query, codec *keyset.Codec, cursor string, size int) (Page[T], error) {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return Page[T]{}, err
+	}
+
+	sorts, err := keysetSorts(q, stmt.Schema)
+	if err != nil {
+		return Page[T]{}, err
+	}
+
+	keys := make([]string, 0, len(sorts))
+	for _, s := range sorts {
+		keys = append(keys, keyset.Key(string(s.column), s.desc))
+	}
+
+	var after *keyset.Cursor
+	if cursor != "" {
+		c, err := codec.Decode(cursor, keys)
+		if err != nil {
+			return Page[T]{}, err
+		}
+		after = &c
+	}
+
+	db := q.apply(r.model(ctx), false)
+	backward := after != nil && after.Backward
+	if after != nil {
+		db = db.Where(keysetAfter(sorts, after.Values, backward))
+	}
+	for _, s := range sorts {
+		db = db.Order(clause.OrderByColumn{Column: s.column.column(), Desc: s.desc != backward, Reorder: false})
+	}
+
+	items := []T{}
+	if err := db.Limit(size + 1).Find(&items).Error; err != nil {
+		return Page[T]{}, err
+	}
+
+	hasNext, hasPrev := keyset.Links(after, len(items), size)
+	items = items[:min(len(items), size)]
+	if backward {
+		slices.Reverse(items)
+	}
+
+	page := Page[T]{
+		Items: items,
+		Next:  "",
+		Prev:  "",
+	}
+	if len(items) == 0 {
+		return page, nil
+	}
+
+	if hasNext {
+		if page.Next, err = keysetCursor(ctx, stmt.Schema, sorts, keys, &items[len(items)-1], false, codec); err != nil {
+			return Page[T]{}, err
+		}
+	}
+	if hasPrev {
+		if page.Prev, err = keysetCursor(ctx, stmt.Schema, sorts, keys, &items[0], true, codec); err != nil {
+			return Page[T]{}, err
+		}
+	}
+
+	return page, nil
+}
+
+// keysetSorts the sorts of q and the primary key columns it doesn't sort by, ascending
+func keysetSorts(q *Query, s *schema.Schema) ([]Sort, error) {
+	sorts := []Sort{}
+	if q != nil {
+		sorts = append(sorts, q.sorts...)
+	}
+
+	for _, field := range s.PrimaryFields {
+		if !slices.ContainsFunc(sorts, func(s Sort) bool { return string(s.column) == field.DBName }) {
+			sorts = append(sorts, Column(field.DBName).Asc())
+		}
+	}
+	if len(sorts) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoPrimaryKey, s.Name)
+	}
+
+	return sorts, nil
+}
+
+// keysetAfter (a > ?) OR (a = ? AND b > ?) ... for the sorts a, b ..., with < for the descending ones
+func keysetAfter(sorts []Sort, values []any, backward bool) clause.Expression {
+	ors := make([]clause.Expression, 0, len(sorts))
+	for i, s := range sorts {
+		ands := make([]clause.Expression, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, clause.Eq{Column: sorts[j].column.column(), Value: values[j]})
+		}
+
+		if s.desc != backward {
+			ands = append(ands, clause.Lt{Column: s.column.column(), Value: values[i]})
+		} else {
+			ands = append(ands, clause.Gt{Column: s.column.column(), Value: values[i]})
+		}
+
+		ors = append(ors, clause.And(ands...))
+	}
+
+	return clause.Or(ors...)
+}
+
+func keysetCursor[T any](ctx context.Context, s *schema.Schema, sorts []Sort, keys []string, item *T, backward bool, codec *keyset.Codec) (string, error) {
+	row := reflect.ValueOf(item).Elem()
+
+	values := make([]any, 0, len(sorts))
+	for _, sort := range sorts {
+		field := s.LookUpField(string(sort.column))
+		if field == nil {
+			return "", fmt.Errorf("%s has no field for the sort column %s", s.Name, sort.column)
+		}
+
+		v, _ := field.ValueOf(ctx, row)
+		if valuer, ok := v.(driver.Valuer); ok {
+			var err error
+			if v, err = valuer.Value(); err != nil {
+				return "", err
+			}
+		}
+		values = append(values, v)
+	}
+
+	return codec.Encode(keyset.Cursor{
+		Keys:     keys,
+		Values:   values,
+		Backward: backward,
+	})
+}
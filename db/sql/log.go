@@ -0,0 +1,127 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"path"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/Lee-Chi/go-sdk/logger"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// QueryLog how the queries are written to the logger package, failed ones as errors and slow ones as warnings
+type QueryLog struct {
+	// Slow threshold of a slow query, 200ms if zero
+	Slow time.Duration
+	// All every query at debug level, as with gorm's Debug()
+	All bool
+	// Redact leaves the parameters out of the logged statements, which are logged with their placeholders
+	Redact bool
+}
+
+const (
+	defaultSlowQuery time.Duration = 200 * time.Millisecond
+)
+
+// WithQueryLog replaces the default QueryLog, which logs the failed and slow queries
+func WithQueryLog(q QueryLog) Option {
+	return func(db *Database) {
+		db.queryLog = q
+	}
+}
+
+// callerPrefixes the frames of gorm, database/sql and this module's db packages are skipped for the caller of a query
+var callerPrefixes = []string{
+	"gorm.io/",
+	"database/sql.",
+	path.Dir(reflect.TypeOf(Database{}).PkgPath()) + "/",
+}
+
+type queryLogger struct {
+	config QueryLog
+	silent bool
+}
+
+func newQueryLogger(config QueryLog) *queryLogger {
+	if config.Slow <= 0 {
+		config.Slow = defaultSlowQuery
+	}
+
+	return &queryLogger{
+		config: config,
+		silent: false,
+	}
+}
+
+func (l *queryLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	mode := *l
+	mode.silent = level == gormlogger.Silent
+	mode.config.All = level >= gormlogger.Info
+
+	return &mode
+}
+
+func (l *queryLogger) Info(ctx context.Context, format string, args ...interface{}) {
+	if !l.silent {
+		logger.Log(logger.Level_Info, logger.CallerOutside(callerPrefixes...), format, args...)
+	}
+}
+
+func (l *queryLogger) Warn(ctx context.Context, format string, args ...interface{}) {
+	if !l.silent {
+		logger.Log(logger.Level_Warn, logger.CallerOutside(callerPrefixes...), format, args...)
+	}
+}
+
+func (l *queryLogger) Error(ctx context.Context, format string, args ...interface{}) {
+	if !l.silent {
+		logger.Log(logger.Level_Error, logger.CallerOutside(callerPrefixes...), format, args...)
+	}
+}
+
+// Trace a not found record is not an error of the query, the caller is told by the error it returns
+func (l *queryLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+
+	var level, reason string
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		level, reason = logger.Level_Error, err.Error()
+	case elapsed > l.config.Slow:
+		level, reason = logger.Level_Warn, "slow query"
+	case l.config.All:
+		level, reason = logger.Level_Debug, "query"
+	default:
+		return
+	}
+
+	sql, rows := fc()
+	logger.Log(level, logger.CallerOutside(callerPrefixes...), "%s | %.3fms | rows:%s | %s",
+		reason, float64(elapsed.Microseconds())/1000, rowsAffected(rows), sql)
+}
+
+// ParamsFilter called by gorm before it writes the parameters into the logged statement
+func (l *queryLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if l.config.Redact {
+		return sql, nil
+	}
+
+	return sql, params
+}
+
+// rowsAffected "-" when the driver doesn't report it
+func rowsAffected(rows int64) string {
+	if rows < 0 {
+		return "-"
+	}
+
+	return strconv.FormatInt(rows, 10)
+}
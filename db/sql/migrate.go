@@ -0,0 +1,541 @@
+package sql
+
+import (
+	"context"
+	"crypto/sha256"
+	stdsql "database/sql"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"math"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidMigration  = fmt.Errorf("invalid migration")
+	ErrMigrationChecksum = fmt.Errorf("applied migration has been modified")
+	ErrNoDownMigration   = fmt.Errorf("migration has no down")
+	ErrMigrationLocked   = fmt.Errorf("migration lock not acquired")
+	ErrMigrationDirty    = fmt.Errorf("migration did not complete, fix the schema and its row in " + migrationTable)
+)
+
+// Migration a version of the schema, loaded from <version>_<name>.up.sql and the optional <version>_<name>.down.sql
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Checksum of the up SQL, an applied migration whose file has changed fails Migrate
+func (m Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.Up))
+	return hex.EncodeToString(sum[:])
+}
+
+// WithMigrations the migration files in dir of fsys, as an embed.FS
+func WithMigrations(fsys fs.FS, dir string) Option {
+	return func(db *Database) {
+		db.migrations = fsys
+		db.migrationDir = dir
+	}
+}
+
+// WithMigrationDir the migration files in a directory
+func WithMigrationDir(dir string) Option {
+	return WithMigrations(os.DirFS(dir), ".")
+}
+
+// LoadMigrations reads the migrations in dir of fsys sorted by version, other files are ignored
+func LoadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		base, up := strings.CutSuffix(entry.Name(), ".up.sql")
+		if !up {
+			var down bool
+			if base, down = strings.CutSuffix(entry.Name(), ".down.sql"); !down {
+				continue
+			}
+		}
+
+		v, name, _ := strings.Cut(base, "_")
+		version, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("%w: %s must start with a positive version", ErrInvalidMigration, entry.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{
+				Version: version,
+				Name:    name,
+				Up:      "",
+				Down:    "",
+			}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("%w: version %d is used by %s and %s", ErrInvalidMigration, version, m.Name, name)
+		}
+
+		if up {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("%w: %d_%s has no up", ErrInvalidMigration, m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+const (
+	migrationTable string = "schema_migrations"
+)
+
+type appliedMigration struct {
+	Version   int64  `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"size:255"`
+	Checksum  string `gorm:"size:64"`
+	AppliedAt time.Time
+	// Dirty while the migration runs, left set by a failure the transaction couldn't roll back
+	Dirty bool
+}
+
+func (appliedMigration) TableName() string {
+	return migrationTable
+}
+
+// MigrationStatus a migration file or an applied migration whose file no longer exists
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	// Modified the file has changed since it was applied
+	Modified bool
+	// Missing applied but there is no file for it
+	Missing bool
+	// Dirty the migration or its rollback failed halfway, as a DDL statement is committed at once on MySQL
+	Dirty bool
+}
+
+// MigrationStep a migration run by Migrate, Rollback when its down was run
+type MigrationStep struct {
+	Migration
+	Rollback bool
+}
+
+type migrateOptions struct {
+	target int64
+	dryRun bool
+}
+
+type MigrateOption func(o *migrateOptions)
+
+// MigrateTo migrates up or down to version instead of the latest, 0 rolls back every migration
+func MigrateTo(version int64) MigrateOption {
+	return func(o *migrateOptions) {
+		o.target = version
+	}
+}
+
+// MigrateDryRun returns the steps Migrate would run without running them
+func MigrateDryRun() MigrateOption {
+	return func(o *migrateOptions) {
+		o.dryRun = true
+	}
+}
+
+// MigrateStatus every migration with whether it has been applied
+func (db *Database) MigrateStatus(ctx context.Context) ([]MigrationStatus, error) {
+	migrations, err := db.loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := db.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return migrationStatus(migrations, applied), nil
+}
+
+// Migrate runs the pending migrations, each in its own transaction, and returns the steps run.
+// MySQL commits every DDL statement at once, so a migration failing there can be partially applied. It stays dirty
+// and Migrate returns ErrMigrationDirty until the schema is fixed by hand and the dirty row is deleted or cleared.
+// Applied migrations above the MigrateTo version are rolled back first, from the latest.
+// Instances migrating at the same time wait for each other on a lock of the server, which SQLite doesn't need.
+// A file is split into statements on the semicolons outside of quotes, comments and dollar quoted bodies. Put the
+// statements with semicolons of their own, as CREATE TRIGGER ... BEGIN ... END, between a "-- StatementBegin" and a
+// "-- StatementEnd" line, DELIMITER is a command of the mysql client which servers don't know.
+func (db *Database) Migrate(ctx context.Context, opts ...MigrateOption) ([]MigrationStep, error) {
+	o := migrateOptions{
+		target: math.MaxInt64,
+		dryRun: false,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	migrations, err := db.loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	if !o.dryRun {
+		unlock, err := db.migrationLock(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+
+		if err := db.Primary().WithContext(ctx).AutoMigrate(&appliedMigration{}); err != nil {
+			return nil, err
+		}
+	}
+
+	applied, err := db.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := migrationStatus(migrations, applied)
+	for _, s := range statuses {
+		if s.Dirty {
+			return nil, fmt.Errorf("%w: %d_%s", ErrMigrationDirty, s.Version, s.Name)
+		}
+		if s.Modified {
+			return nil, fmt.Errorf("%w: %d_%s", ErrMigrationChecksum, s.Version, s.Name)
+		}
+	}
+
+	steps := []MigrationStep{}
+	for i := len(statuses) - 1; i >= 0; i-- {
+		s := statuses[i]
+		if !s.Applied || s.Version <= o.target {
+			continue
+		}
+		if s.Down == "" {
+			return nil, fmt.Errorf("%w: %d_%s", ErrNoDownMigration, s.Version, s.Name)
+		}
+		steps = append(steps, MigrationStep{Migration: s.Migration, Rollback: true})
+	}
+	for _, s := range statuses {
+		if !s.Applied && s.Version <= o.target {
+			steps = append(steps, MigrationStep{Migration: s.Migration, Rollback: false})
+		}
+	}
+
+	if o.dryRun {
+		return steps, nil
+	}
+
+	for i, step := range steps {
+		if err := db.runMigration(ctx, step); err != nil {
+			return steps[:i], fmt.Errorf("migration %d_%s: %w", step.Version, step.Name, err)
+		}
+	}
+
+	return steps, nil
+}
+
+func (db *Database) loadMigrations() ([]Migration, error) {
+	if db.migrations == nil {
+		return nil, fmt.Errorf("%w: no migrations, use WithMigrations", ErrInvalidMigration)
+	}
+
+	return LoadMigrations(db.migrations, db.migrationDir)
+}
+
+// appliedMigrations reads the table from the primary, none if it doesn't exist yet
+func (db *Database) appliedMigrations(ctx context.Context) ([]appliedMigration, error) {
+	primary := db.Primary().WithContext(ctx)
+	if !primary.Migrator().HasTable(&appliedMigration{}) {
+		return nil, nil
+	}
+
+	applied := []appliedMigration{}
+	if err := primary.Order("version").Find(&applied).Error; err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+func migrationStatus(migrations []Migration, applied []appliedMigration) []MigrationStatus {
+	byVersion := map[int64]appliedMigration{}
+	for _, a := range applied {
+		byVersion[a.Version] = a
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations)+len(applied))
+	for _, m := range migrations {
+		a, ok := byVersion[m.Version]
+		delete(byVersion, m.Version)
+
+		statuses = append(statuses, MigrationStatus{
+			Migration: m,
+			Applied:   ok,
+			AppliedAt: a.AppliedAt,
+			Modified:  ok && a.Checksum != m.Checksum(),
+			Missing:   false,
+			Dirty:     a.Dirty,
+		})
+	}
+
+	for _, a := range byVersion {
+		statuses = append(statuses, MigrationStatus{
+			Migration: Migration{
+				Version: a.Version,
+				Name:    a.Name,
+				Up:      "",
+				Down:    "",
+			},
+			Applied:   true,
+			AppliedAt: a.AppliedAt,
+			Modified:  false,
+			Missing:   true,
+			Dirty:     a.Dirty,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses
+}
+
+func (db *Database) runMigration(ctx context.Context, step MigrationStep) error {
+	script := step.Up
+	if step.Rollback {
+		script = step.Down
+	}
+
+	return db.Transaction(ctx, func(tx *Tx) error {
+		// the dirty row is written first, the implicit commit of the first DDL statement on MySQL commits it too,
+		// other servers roll it back with the statements
+		var err error
+		if step.Rollback {
+			err = tx.DB.Model(&appliedMigration{Version: step.Version}).Update("dirty", true).Error
+		} else {
+			err = tx.DB.Create(&appliedMigration{
+				Version:   step.Version,
+				Name:      step.Name,
+				Checksum:  step.Checksum(),
+				AppliedAt: time.Now().UTC(),
+				Dirty:     true,
+			}).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, stmt := range splitStatements(script, db.driver.Name() == DriverName_MySQL) {
+			if err := tx.DB.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+
+		if step.Rollback {
+			return tx.DB.Delete(&appliedMigration{}, step.Version).Error
+		}
+
+		return tx.DB.Model(&appliedMigration{Version: step.Version}).Updates(map[string]any{
+			"applied_at": time.Now().UTC(),
+			"dirty":      false,
+		}).Error
+	})
+}
+
+// migrationLock holds a session lock of the server on a dedicated connection until unlock is called
+func (db *Database) migrationLock(ctx context.Context) (func(), error) {
+	var lock, unlock string
+	var arg any
+
+	name := "go-sdk:migrate:" + db.dbName
+	switch db.driver.Name() {
+	case DriverName_MySQL:
+		// lock names are limited to 64 characters
+		if len(name) > 64 {
+			name = name[:64]
+		}
+		lock, unlock, arg = "SELECT GET_LOCK(?, -1)", "SELECT RELEASE_LOCK(?)", name
+	case DriverName_Postgres:
+		key := fnv.New64a()
+		key.Write([]byte(name))
+		lock, unlock, arg = "SELECT pg_advisory_lock($1)", "SELECT pg_advisory_unlock($1)", int64(key.Sum64())
+	case DriverName_SQLServer:
+		lock = "DECLARE @r int; EXEC @r = sp_getapplock @Resource = @p1, @LockMode = 'Exclusive', @LockOwner = 'Session', @LockTimeout = -1; SELECT @r"
+		unlock = "EXEC sp_releaseapplock @Resource = @p1, @LockOwner = 'Session'"
+		arg = name
+	default:
+		// SQLite serializes the writers itself
+		return func() {}, nil
+	}
+
+	sqlDB, err := db.DB.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result stdsql.NullInt64
+	if db.driver.Name() == DriverName_Postgres {
+		// pg_advisory_lock returns void, which pgx can't scan
+		_, err = conn.ExecContext(ctx, lock, arg)
+	} else {
+		err = conn.QueryRowContext(ctx, lock, arg).Scan(&result)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("%w: %w", ErrMigrationLocked, err)
+	}
+	// GET_LOCK returns 1 and sp_getapplock 0 or 1 once acquired
+	if result.Int64 < 0 || (db.driver.Name() == DriverName_MySQL && result.Int64 != 1) {
+		conn.Close()
+		return nil, fmt.Errorf("%w: %d", ErrMigrationLocked, result.Int64)
+	}
+
+	return func() {
+		conn.ExecContext(context.Background(), unlock, arg)
+		conn.Close()
+	}, nil
+}
+
+const (
+	// statementBegin and statementEnd lines keep what is between them as one statement, for the bodies of triggers
+	// and procedures which have semicolons inside
+	statementBegin string = "-- StatementBegin"
+	statementEnd   string = "-- StatementEnd"
+)
+
+// splitStatements splits script on the semicolons outside of quotes, comments, dollar quoted bodies and statement
+// blocks, mysql for its # comments and the backslash escaping a quote inside a string
+func splitStatements(script string, mysql bool) []string {
+	statements := []string{}
+	start := 0
+	// content whether there is more than comments since start, a server refuses a statement of comments only
+	content := false
+
+	add := func(stmt string) {
+		if stmt = strings.TrimSpace(stmt); stmt != "" && content {
+			statements = append(statements, stmt)
+		}
+		content = false
+	}
+
+	for i := 0; i < len(script); i++ {
+		switch c := script[i]; {
+		case c == ';':
+			add(script[start:i])
+			start = i + 1
+		case c == '\'' || c == '"' || c == '`':
+			content = true
+			for i++; i < len(script); i++ {
+				if mysql && script[i] == '\\' {
+					i++
+					continue
+				}
+				if script[i] == c {
+					// a doubled quote is an escaped one
+					if i+1 < len(script) && script[i+1] == c {
+						i++
+						continue
+					}
+					break
+				}
+			}
+		case c == '-' && strings.HasPrefix(script[i:], "--"), mysql && c == '#':
+			eol := strings.IndexByte(script[i:], '\n')
+			if eol < 0 {
+				eol = len(script) - i
+			}
+
+			if strings.TrimSpace(script[i:i+eol]) != statementBegin {
+				i += eol
+				continue
+			}
+
+			add(script[start:i])
+			body := i + eol
+			content = true
+			if end := strings.Index(script[body:], statementEnd); end >= 0 {
+				add(strings.TrimSuffix(strings.TrimSpace(script[body:body+end]), ";"))
+				i = body + end + len(statementEnd) - 1
+			} else {
+				add(strings.TrimSuffix(strings.TrimSpace(script[body:]), ";"))
+				i = len(script)
+			}
+			start = i + 1
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			if end := strings.Index(script[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(script)
+			}
+		case c == '$':
+			content = true
+			// $tag$ ... $tag$ of PostgreSQL function bodies
+			end := strings.IndexByte(script[i+1:], '$')
+			if end < 0 || !dollarTag(script[i+1:i+1+end]) {
+				continue
+			}
+			tag := script[i : i+end+2]
+			if close := strings.Index(script[i+len(tag):], tag); close >= 0 {
+				i += len(tag) + close + len(tag) - 1
+			} else {
+				i = len(script)
+			}
+		case c != ' ' && c != '\t' && c != '\r' && c != '\n':
+			content = true
+		}
+	}
+	add(script[min(start, len(script)):])
+
+	return statements
+}
+
+func dollarTag(tag string) bool {
+	for i, c := range tag {
+		if c != '_' && !('a' <= c && c <= 'z') && !('A' <= c && c <= 'Z') && (i == 0 || !('0' <= c && c <= '9')) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,170 @@
+package sql
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// Endpoint the address of a read replica, it is opened with the driver, database name and credentials of the primary
+type Endpoint struct {
+	IP   string
+	Port string
+}
+
+// WithReplicas routes reads to the replicas, writes and transactions always go to the primary
+func WithReplicas(endpoints ...Endpoint) Option {
+	return func(db *Database) {
+		db.replicas = append(db.replicas, endpoints...)
+	}
+}
+
+const (
+	ReadPolicy_Random       string = "random"
+	ReadPolicy_RoundRobin   string = "round_robin"
+	ReadPolicy_LeastLatency string = "least_latency"
+)
+
+// WithReadPolicy how a replica is chosen for a read, ReadPolicy_Random if not given
+func WithReadPolicy(policy string) Option {
+	return func(db *Database) {
+		db.readPolicy = policy
+	}
+}
+
+// Primary reads from the primary, for a read which must see a write just made
+func (db *Database) Primary() *gorm.DB {
+	return db.DB.Clauses(dbresolver.Write)
+}
+
+// registerReplicas called by Open once the primary is connected
+func (db *Database) registerReplicas(gormDB *gorm.DB) error {
+	if len(db.replicas) == 0 {
+		return nil
+	}
+
+	replicas := make([]gorm.Dialector, 0, len(db.replicas))
+	for _, endpoint := range db.replicas {
+		replica := *db
+		replica.ip = endpoint.IP
+		replica.port = endpoint.Port
+
+		replicas = append(replicas, db.driver.Dialector(db.driver.DSN(&replica)))
+	}
+
+	resolver := dbresolver.Register(dbresolver.Config{
+		Sources:           nil,
+		Replicas:          replicas,
+		Policy:            newReadPolicy(db.readPolicy),
+		TraceResolverMode: false,
+	})
+
+	if db.pool.maxOpenConns > 0 {
+		resolver.SetMaxOpenConns(db.pool.maxOpenConns)
+	}
+	if db.pool.maxIdleConns > 0 {
+		resolver.SetMaxIdleConns(db.pool.maxIdleConns)
+	}
+	if db.pool.connMaxLifetime > 0 {
+		resolver.SetConnMaxLifetime(db.pool.connMaxLifetime)
+	}
+	if db.pool.connMaxIdleTime > 0 {
+		resolver.SetConnMaxIdleTime(db.pool.connMaxIdleTime)
+	}
+
+	if err := gormDB.Use(resolver); err != nil {
+		return err
+	}
+	db.resolver = resolver
+
+	return nil
+}
+
+func newReadPolicy(policy string) dbresolver.Policy {
+	switch policy {
+	case ReadPolicy_RoundRobin:
+		return &roundRobin{}
+	case ReadPolicy_LeastLatency:
+		return &leastLatency{
+			interval:  5 * time.Second,
+			timeout:   time.Second,
+			mu:        sync.Mutex{},
+			latencies: map[gorm.ConnPool]time.Duration{},
+			probed:    time.Time{},
+			probing:   atomic.Bool{},
+		}
+	default:
+		return dbresolver.RandomPolicy{}
+	}
+}
+
+type roundRobin struct {
+	next atomic.Uint64
+}
+
+func (p *roundRobin) Resolve(pools []gorm.ConnPool) gorm.ConnPool {
+	return pools[(p.next.Add(1)-1)%uint64(len(pools))]
+}
+
+// leastLatency picks the replica with the lowest ping latency, the replicas are pinged in the background
+// at most once per interval while reads are made, a replica which fails the ping is only picked if all of them fail
+type leastLatency struct {
+	interval time.Duration
+	timeout  time.Duration
+
+	mu        sync.Mutex
+	latencies map[gorm.ConnPool]time.Duration
+	probed    time.Time
+	probing   atomic.Bool
+}
+
+func (p *leastLatency) Resolve(pools []gorm.ConnPool) gorm.ConnPool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if time.Since(p.probed) > p.interval && p.probing.CompareAndSwap(false, true) {
+		go p.probe(pools)
+	}
+
+	// a replica not probed yet counts as the fastest so it gets tried
+	best := pools[0]
+	bestLatency := p.latencies[best]
+	for _, pool := range pools[1:] {
+		if latency := p.latencies[pool]; latency < bestLatency {
+			best, bestLatency = pool, latency
+		}
+	}
+
+	return best
+}
+
+func (p *leastLatency) probe(pools []gorm.ConnPool) {
+	defer p.probing.Store(false)
+
+	latencies := make(map[gorm.ConnPool]time.Duration, len(pools))
+	for _, pool := range pools {
+		latencies[pool] = time.Duration(math.MaxInt64)
+
+		pinger, ok := pool.(interface{ PingContext(context.Context) error })
+		if !ok {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+		start := time.Now()
+		if err := pinger.PingContext(ctx); err == nil {
+			latencies[pool] = time.Since(start)
+		}
+		cancel()
+	}
+
+	p.mu.Lock()
+	p.latencies = latencies
+	p.probed = time.Now()
+	p.mu.Unlock()
+}
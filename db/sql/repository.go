@@ -0,0 +1,460 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrNotFound     = fmt.Errorf("record not found")
+	ErrNoPrimaryKey = fmt.Errorf("model has no primary key")
+)
+
+// Filter a condition of a Query, built from a Col or with Raw
+type Filter struct {
+	expr clause.Expression
+}
+
+// Raw a condition written in SQL, as Raw("age > ? AND age < ?", 18, 65)
+func Raw(query string, args ...any) Filter {
+	return Filter{
+		expr: clause.Expr{SQL: query, Vars: args, WithoutParentheses: false},
+	}
+}
+
+// Or any of the filters
+func Or(filters ...Filter) Filter {
+	exprs := make([]clause.Expression, 0, len(filters))
+	for _, f := range filters {
+		exprs = append(exprs, f.expr)
+	}
+
+	return Filter{
+		expr: clause.Or(exprs...),
+	}
+}
+
+func Not(filter Filter) Filter {
+	return Filter{
+		expr: clause.Not(filter.expr),
+	}
+}
+
+// Col a column of the table, quoted by the driver
+type Col string
+
+func Column(name string) Col {
+	return Col(name)
+}
+
+func (c Col) column() clause.Column {
+	return clause.Column{Table: clause.CurrentTable, Name: string(c), Alias: "", Raw: false}
+}
+
+// Eq IS NULL if value is nil
+func (c Col) Eq(value any) Filter {
+	return Filter{expr: clause.Eq{Column: c.column(), Value: value}}
+}
+
+func (c Col) Ne(value any) Filter {
+	return Filter{expr: clause.Neq{Column: c.column(), Value: value}}
+}
+
+func (c Col) Gt(value any) Filter {
+	return Filter{expr: clause.Gt{Column: c.column(), Value: value}}
+}
+
+func (c Col) Gte(value any) Filter {
+	return Filter{expr: clause.Gte{Column: c.column(), Value: value}}
+}
+
+func (c Col) Lt(value any) Filter {
+	return Filter{expr: clause.Lt{Column: c.column(), Value: value}}
+}
+
+func (c Col) Lte(value any) Filter {
+	return Filter{expr: clause.Lte{Column: c.column(), Value: value}}
+}
+
+func (c Col) In(values ...any) Filter {
+	return Filter{expr: clause.IN{Column: c.column(), Values: values}}
+}
+
+func (c Col) NotIn(values ...any) Filter {
+	return Filter{expr: clause.Not(clause.IN{Column: c.column(), Values: values})}
+}
+
+// Like pattern uses % and _ as wildcards
+func (c Col) Like(pattern string) Filter {
+	return Filter{expr: clause.Like{Column: c.column(), Value: pattern}}
+}
+
+func (c Col) Asc() Sort {
+	return Sort{column: c, desc: false}
+}
+
+func (c Col) Desc() Sort {
+	return Sort{column: c, desc: true}
+}
+
+type Sort struct {
+	column Col
+	desc   bool
+}
+
+// Query the filters, sorts and page of List and Count, a nil Query matches every row
+type Query struct {
+	filters     []Filter
+	sorts       []Sort
+	offset      int
+	limit       int
+	withDeleted bool
+}
+
+func NewQuery() *Query {
+	return &Query{
+		filters:     []Filter{},
+		sorts:       []Sort{},
+		offset:      -1,
+		limit:       -1,
+		withDeleted: false,
+	}
+}
+
+// Where every filter must match
+func (q *Query) Where(filters ...Filter) *Query {
+	q.filters = append(q.filters, filters...)
+	return q
+}
+
+func (q *Query) Sort(sorts ...Sort) *Query {
+	q.sorts = append(q.sorts, sorts...)
+	return q
+}
+
+func (q *Query) Offset(offset int) *Query {
+	q.offset = offset
+	return q
+}
+
+func (q *Query) Limit(limit int) *Query {
+	q.limit = limit
+	return q
+}
+
+// Page page starts from 1
+func (q *Query) Page(page int, size int) *Query {
+	q.offset = (max(page, 1) - 1) * size
+	q.limit = size
+	return q
+}
+
+// WithDeleted includes the soft deleted rows
+func (q *Query) WithDeleted() *Query {
+	q.withDeleted = true
+	return q
+}
+
+func (q *Query) apply(db *gorm.DB, page bool) *gorm.DB {
+	if q == nil {
+		return db
+	}
+
+	if q.withDeleted {
+		db = db.Unscoped()
+	}
+
+	exprs := make([]clause.Expression, 0, len(q.filters))
+	for _, f := range q.filters {
+		exprs = append(exprs, f.expr)
+	}
+	if len(exprs) > 0 {
+		db = db.Clauses(clause.Where{Exprs: exprs})
+	}
+
+	if !page {
+		return db
+	}
+
+	for _, s := range q.sorts {
+		db = db.Order(clause.OrderByColumn{Column: s.column.column(), Desc: s.desc, Reorder: false})
+	}
+	if q.offset >= 0 {
+		db = db.Offset(q.offset)
+	}
+	if q.limit >= 0 {
+		db = db.Limit(q.limit)
+	}
+
+	return db
+}
+
+// Repository the CRUD of the model T, a struct mapped by gorm with a primary key.
+// If T has a gorm.DeletedAt field Delete soft deletes and the deleted rows are left out unless WithDeleted.
+type Repository[T any] struct {
+	db *gorm.DB
+}
+
+// NewRepository db must be open
+func NewRepository[T any](db *Database) *Repository[T] {
+	return &Repository[T]{
+		db: db.DB,
+	}
+}
+
+// WithTx the repository running its statements in the transaction
+func (r *Repository[T]) WithTx(tx *Tx) *Repository[T] {
+	return &Repository[T]{
+		db: tx.DB,
+	}
+}
+
+func (r *Repository[T]) model(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(new(T))
+}
+
+func (r *Repository[T]) primaryKeys() ([]clause.Column, error) {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, err
+	}
+	if len(stmt.Schema.PrimaryFields) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoPrimaryKey, stmt.Schema.Name)
+	}
+
+	columns := make([]clause.Column, 0, len(stmt.Schema.PrimaryFields))
+	for _, field := range stmt.Schema.PrimaryFields {
+		columns = append(columns, clause.Column{Table: "", Name: field.DBName, Alias: "", Raw: false})
+	}
+
+	return columns, nil
+}
+
+// columns of the mask of Go field or column names
+func (r *Repository[T]) columns(fields []string) ([]string, error) {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, err
+	}
+
+	columns := make([]string, 0, len(fields))
+	for _, name := range fields {
+		field := stmt.Schema.LookUpField(name)
+		if field == nil {
+			return nil, fmt.Errorf("%s has no field %s", stmt.Schema.Name, name)
+		}
+		columns = append(columns, field.DBName)
+	}
+
+	return columns, nil
+}
+
+// generated the columns an Update without a mask leaves alone, the primary keys and the creation times
+func (r *Repository[T]) generated() ([]string, error) {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, err
+	}
+
+	columns := []string{}
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName != "" && (field.PrimaryKey || field.AutoCreateTime > 0) {
+			columns = append(columns, field.DBName)
+		}
+	}
+
+	return columns, nil
+}
+
+// exists whether there is a row with the primary key of entity
+func (r *Repository[T]) exists(ctx context.Context, entity *T) (bool, error) {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(entity); err != nil {
+		return false, err
+	}
+	if len(stmt.Schema.PrimaryFields) == 0 {
+		return false, fmt.Errorf("%w: %s", ErrNoPrimaryKey, stmt.Schema.Name)
+	}
+
+	db := r.model(ctx)
+	for _, field := range stmt.Schema.PrimaryFields {
+		value, _ := field.ValueOf(ctx, reflect.ValueOf(entity).Elem())
+		column := clause.Column{Table: clause.CurrentTable, Name: field.DBName, Alias: "", Raw: false}
+		db = db.Where(clause.Eq{Column: column, Value: value})
+	}
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *Repository[T]) byID(ctx context.Context, id any) (*gorm.DB, error) {
+	keys, err := r.primaryKeys()
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) > 1 {
+		return nil, fmt.Errorf("%w: composite primary key, use List", ErrNoPrimaryKey)
+	}
+
+	keys[0].Table = clause.CurrentTable
+
+	return r.model(ctx).Where(clause.Eq{Column: keys[0], Value: id}), nil
+}
+
+// Get the row of the primary key id, ErrNotFound if there is none
+func (r *Repository[T]) Get(ctx context.Context, id any) (T, error) {
+	var entity T
+
+	db, err := r.byID(ctx, id)
+	if err != nil {
+		return entity, err
+	}
+
+	if err := db.Take(&entity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity, ErrNotFound
+		}
+		return entity, err
+	}
+
+	return entity, nil
+}
+
+// First the first row of the query, ErrNotFound if there is none
+func (r *Repository[T]) First(ctx context.Context, q *Query) (T, error) {
+	var entity T
+
+	found := []T{}
+	if err := q.apply(r.model(ctx), true).Limit(1).Find(&found).Error; err != nil {
+		return entity, err
+	}
+	if len(found) == 0 {
+		return entity, ErrNotFound
+	}
+
+	return found[0], nil
+}
+
+func (r *Repository[T]) List(ctx context.Context, q *Query) ([]T, error) {
+	entities := []T{}
+	if err := q.apply(r.model(ctx), true).Find(&entities).Error; err != nil {
+		return nil, err
+	}
+
+	return entities, nil
+}
+
+// Count the rows matching the filters of q, its sorts and page are ignored
+func (r *Repository[T]) Count(ctx context.Context, q *Query) (int64, error) {
+	var count int64
+	if err := q.apply(r.model(ctx), false).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Create inserts entity and fills in the generated primary key
+func (r *Repository[T]) Create(ctx context.Context, entity *T) error {
+	return r.db.WithContext(ctx).Create(entity).Error
+}
+
+// Update writes the fields of entity to the row of its primary key, fields is the mask of the Go field or column names
+// to write, zero values included, every field but the primary key and the creation time if empty.
+// ErrNotFound if no row has the primary key.
+func (r *Repository[T]) Update(ctx context.Context, entity *T, fields ...string) error {
+	db := r.db.WithContext(ctx).Model(entity)
+	if len(fields) == 0 {
+		omit, err := r.generated()
+		if err != nil {
+			return err
+		}
+		db = db.Select("*").Omit(omit...)
+	} else {
+		columns, err := r.columns(fields)
+		if err != nil {
+			return err
+		}
+		db = db.Select(columns)
+	}
+
+	result := db.Updates(entity)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	// MySQL reports no affected row for a row whose values are unchanged too
+	found, err := r.exists(ctx, entity)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Upsert inserts entity or, if its primary key exists, updates the fields of the mask, every field if empty
+func (r *Repository[T]) Upsert(ctx context.Context, entity *T, fields ...string) error {
+	keys, err := r.primaryKeys()
+	if err != nil {
+		return err
+	}
+
+	onConflict := clause.OnConflict{
+		Columns:   keys,
+		UpdateAll: len(fields) == 0,
+	}
+	if len(fields) > 0 {
+		columns, err := r.columns(fields)
+		if err != nil {
+			return err
+		}
+		onConflict.DoUpdates = clause.AssignmentColumns(columns)
+	}
+
+	return r.db.WithContext(ctx).Clauses(onConflict).Create(entity).Error
+}
+
+// Delete the row of the primary key id, soft deleted if T has a gorm.DeletedAt field, ErrNotFound if there is none
+func (r *Repository[T]) Delete(ctx context.Context, id any) error {
+	return r.delete(ctx, id, false)
+}
+
+// HardDelete removes the row of the primary key id even if T is soft deleted
+func (r *Repository[T]) HardDelete(ctx context.Context, id any) error {
+	return r.delete(ctx, id, true)
+}
+
+func (r *Repository[T]) delete(ctx context.Context, id any, hard bool) error {
+	db, err := r.byID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if hard {
+		db = db.Unscoped()
+	}
+
+	result := db.Delete(new(T))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Lee-Chi/go-sdk/db/keyset"
+)
+
+type item struct {
+	ID        int64
+	Name      string
+	Count     int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func newItemRepository(t *testing.T) *Repository[item] {
+	t.Helper()
+
+	db := NewSQLite(Memory, WithQueryLog(QueryLog{Slow: time.Minute, All: false, Redact: false}))
+	if err := db.Open(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := db.DB.AutoMigrate(&item{}); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewRepository[item](db)
+}
+
+func TestRepositoryUpdate(t *testing.T) {
+	ctx := context.Background()
+	r := newItemRepository(t)
+
+	created := item{Name: "a", Count: 1}
+	if err := r.Create(ctx, &created); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		entity item
+		fields []string
+		want   item
+		err    string
+	}{
+		{"every field", item{ID: created.ID, Name: "b", Count: 2}, nil, item{Name: "b", Count: 2}, ""},
+		{"zero value of the mask", item{ID: created.ID, Name: "ignored", Count: 0}, []string{"Count"}, item{Name: "b", Count: 0}, ""},
+		{"column name", item{ID: created.ID, Name: "c"}, []string{"name"}, item{Name: "c", Count: 0}, ""},
+		{"unchanged", item{ID: created.ID, Name: "c"}, []string{"Name"}, item{Name: "c", Count: 0}, ""},
+		{"unknown field", item{ID: created.ID, Name: "d"}, []string{"Nope"}, item{Name: "c", Count: 0}, "item has no field Nope"},
+		{"missing row", item{ID: created.ID + 1, Name: "d"}, nil, item{Name: "c", Count: 0}, ErrNotFound.Error()},
+		{"missing row with a mask", item{ID: created.ID + 1, Name: "d"}, []string{"Name"}, item{Name: "c", Count: 0}, ErrNotFound.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.Update(ctx, &tt.entity, tt.fields...)
+			if msg := fmt.Sprint(err); err != nil && msg != tt.err || err == nil && tt.err != "" {
+				t.Fatalf("error = %s, want %s", msg, tt.err)
+			}
+
+			got, err := r.Get(ctx, created.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Name != tt.want.Name || got.Count != tt.want.Count {
+				t.Fatalf("got {%s %d}, want {%s %d}", got.Name, got.Count, tt.want.Name, tt.want.Count)
+			}
+			// an Update without a mask must not write the zero creation time of the entity
+			if !got.CreatedAt.Equal(created.CreatedAt) {
+				t.Fatalf("created_at = %s, want %s", got.CreatedAt, created.CreatedAt)
+			}
+		})
+	}
+}
+
+type binaryItem struct {
+	ID   []byte `gorm:"primaryKey;size:16"`
+	Name string
+}
+
+func TestRepositoryPaginateBinaryKey(t *testing.T) {
+	ctx := context.Background()
+
+	db := NewSQLite(Memory)
+	if err := db.Open(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.DB.AutoMigrate(&binaryItem{}); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRepository[binaryItem](db)
+	for i := byte(1); i <= 5; i++ {
+		if err := r.Create(ctx, &binaryItem{ID: []byte{0x00, i}, Name: "same"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	codec := keyset.NewCodec([]byte("0123456789abcdef0123456789abcdef"))
+	q := NewQuery().Sort(Column("name").Asc())
+
+	names := []byte{}
+	cursor := ""
+	for page := 0; page < 5; page++ {
+		p, err := r.Paginate(ctx, q, codec, cursor, 2)
+		if err != nil {
+			t.Fatalf("page %d: %v", page+1, err)
+		}
+		for _, item := range p.Items {
+			names = append(names, item.ID[1])
+		}
+		if cursor = p.Next; cursor == "" {
+			break
+		}
+	}
+
+	if string(names) != string([]byte{1, 2, 3, 4, 5}) {
+		t.Fatalf("paged through %v, want [1 2 3 4 5]", names)
+	}
+}
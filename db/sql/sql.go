@@ -1,10 +1,13 @@
 package sql
 
 import (
-	"fmt"
+	stdsql "database/sql"
+	"io"
+	"io/fs"
+	"time"
 
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
 )
 
 type Database struct {
@@ -15,10 +18,59 @@ type Database struct {
 	dbName   string
 	user     string
 	password string
+
+	driver Driver
+	pool   pool
+
+	replicas   []Endpoint
+	readPolicy string
+	resolver   *dbresolver.DBResolver
+
+	migrations   fs.FS
+	migrationDir string
+
+	openRetry openRetry
+	queryLog  QueryLog
+}
+
+// pool the settings of the underlying *sql.DB, zero values keep the database/sql defaults
+type pool struct {
+	maxOpenConns    int
+	maxIdleConns    int
+	connMaxLifetime time.Duration
+	connMaxIdleTime time.Duration
+}
+
+type Option func(db *Database)
+
+// WithMaxOpenConns keep it below the max_connections of the server divided by the number of instances
+func WithMaxOpenConns(n int) Option {
+	return func(db *Database) {
+		db.pool.maxOpenConns = n
+	}
+}
+
+func WithMaxIdleConns(n int) Option {
+	return func(db *Database) {
+		db.pool.maxIdleConns = n
+	}
 }
 
-func New(ip string, port string, dbName string, user string, password string) *Database {
-	return &Database{
+// WithConnMaxLifetime keep it below the wait_timeout of the server
+func WithConnMaxLifetime(d time.Duration) Option {
+	return func(db *Database) {
+		db.pool.connMaxLifetime = d
+	}
+}
+
+func WithConnMaxIdleTime(d time.Duration) Option {
+	return func(db *Database) {
+		db.pool.connMaxIdleTime = d
+	}
+}
+
+func New(ip string, port string, dbName string, user string, password string, opts ...Option) *Database {
+	db := &Database{
 		DB: nil,
 
 		ip:       ip,
@@ -26,12 +78,52 @@ func New(ip string, port string, dbName string, user string, password string) *D
 		dbName:   dbName,
 		user:     user,
 		password: password,
+
+		driver: MySQL{},
+		pool:   pool{},
+
+		replicas:   nil,
+		readPolicy: ReadPolicy_Random,
+		resolver:   nil,
+
+		migrations:   nil,
+		migrationDir: "",
+
+		openRetry: openRetry{},
+		queryLog: QueryLog{
+			Slow:   defaultSlowQuery,
+			All:    false,
+			Redact: false,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(db)
 	}
+
+	return db
+}
+
+// Driver the driver the database is opened with, MySQL unless WithDriver is given
+func (db *Database) Driver() Driver {
+	return db.driver
 }
+
+// Open connects to the primary and the replicas, see WithOpenRetry to wait for a server which is starting
 func (db *Database) Open() error {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", db.user, db.password, db.ip, db.port, db.dbName)
-	gormDB, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	return db.openWithRetry()
+}
+
+func (db *Database) open() error {
+	gormDB, err := gorm.Open(db.driver.Dialector(db.driver.DSN(db)), &gorm.Config{
+		Logger: newQueryLogger(db.queryLog),
+	})
 	if err != nil {
+		if gormDB != nil {
+			if sqlDB, err := gormDB.DB(); err == nil {
+				sqlDB.Close()
+			}
+		}
 		return err
 	}
 
@@ -40,7 +132,15 @@ func (db *Database) Open() error {
 		return err
 	}
 
+	db.pool.apply(sqlDB)
+
 	if err := sqlDB.Ping(); err != nil {
+		sqlDB.Close()
+		return err
+	}
+
+	if err := db.registerReplicas(gormDB); err != nil {
+		sqlDB.Close()
 		return err
 	}
 
@@ -49,11 +149,50 @@ func (db *Database) Open() error {
 	return nil
 }
 
+func (p pool) apply(sqlDB *stdsql.DB) {
+	if p.maxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(p.maxOpenConns)
+	}
+	if p.maxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(p.maxIdleConns)
+	}
+	if p.connMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(p.connMaxLifetime)
+	}
+	if p.connMaxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(p.connMaxIdleTime)
+	}
+}
+
+// Stats the connection pool statistics, zero before Open
+func (db *Database) Stats() stdsql.DBStats {
+	if db.DB == nil {
+		return stdsql.DBStats{}
+	}
+
+	sqlDB, err := db.DB.DB()
+	if err != nil {
+		return stdsql.DBStats{}
+	}
+
+	return sqlDB.Stats()
+}
+
 func (d *Database) Close() error {
 	db, err := d.DB.DB()
 	if err != nil {
 		return err
 	}
 
+	if d.resolver != nil {
+		// the pools of the replicas, closing the primary twice is a no-op
+		d.resolver.Call(func(pool gorm.ConnPool) error {
+			if closer, ok := pool.(io.Closer); ok {
+				closer.Close()
+			}
+			return nil
+		})
+	}
+
 	return db.Close()
 }
@@ -0,0 +1,133 @@
+package sql
+
+import (
+	"context"
+	stdsql "database/sql"
+	"errors"
+	"time"
+
+	"github.com/Lee-Chi/go-sdk/internal/backoff"
+	gomysql "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// Tx a transaction, run every statement through DB
+type Tx struct {
+	DB *gorm.DB
+}
+
+// Transaction runs fn in a savepoint of the transaction, only the savepoint is rolled back if fn fails
+func (tx *Tx) Transaction(fn func(tx *Tx) error) error {
+	return tx.DB.Transaction(func(db *gorm.DB) error {
+		return fn(&Tx{DB: db})
+	})
+}
+
+type txOptions struct {
+	isolation  stdsql.IsolationLevel
+	readOnly   bool
+	maxRetries int
+	backoff    time.Duration
+}
+
+type TxOption func(o *txOptions)
+
+func TxIsolation(level stdsql.IsolationLevel) TxOption {
+	return func(o *txOptions) {
+		o.isolation = level
+	}
+}
+
+func TxReadOnly() TxOption {
+	return func(o *txOptions) {
+		o.readOnly = true
+	}
+}
+
+// TxRetry reruns the whole transaction up to maxRetries times on deadlocks, lock wait timeouts and serialization
+// failures, waiting backoff before the first retry and doubling it for each next one
+func TxRetry(maxRetries int, backoff time.Duration) TxOption {
+	return func(o *txOptions) {
+		o.maxRetries = maxRetries
+		o.backoff = backoff
+	}
+}
+
+// Transaction commits if fn returns nil and rolls back if it returns an error or panics, the panic is propagated.
+// With TxRetry fn may run more than once, so it must not have side effects outside the transaction.
+func (db *Database) Transaction(ctx context.Context, fn func(tx *Tx) error, opts ...TxOption) error {
+	o := txOptions{
+		isolation:  stdsql.LevelDefault,
+		readOnly:   false,
+		maxRetries: 0,
+		backoff:    0,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	b := backoff.New(o.backoff, 0)
+	for attempt := 0; ; attempt++ {
+		err := db.transaction(ctx, fn, o)
+		if err == nil || attempt >= o.maxRetries || !Retryable(err) {
+			return err
+		}
+
+		if !b.Wait(ctx) {
+			return errors.Join(err, ctx.Err())
+		}
+	}
+}
+
+func (db *Database) transaction(ctx context.Context, fn func(tx *Tx) error, o txOptions) (err error) {
+	gormTx := db.DB.WithContext(ctx).Begin(&stdsql.TxOptions{
+		Isolation: o.isolation,
+		ReadOnly:  o.readOnly,
+	})
+	if gormTx.Error != nil {
+		return gormTx.Error
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			gormTx.Rollback()
+		}
+	}()
+
+	if err := fn(&Tx{DB: gormTx}); err != nil {
+		return err
+	}
+
+	if err := gormTx.Commit().Error; err != nil {
+		return err
+	}
+	committed = true
+
+	return nil
+}
+
+const (
+	mysql_LockWaitTimeout uint16 = 1205
+	mysql_Deadlock        uint16 = 1213
+
+	postgres_SerializationFailure string = "40001"
+	postgres_Deadlock             string = "40P01"
+)
+
+// Retryable reports whether err is a deadlock, lock wait timeout or serialization failure,
+// after which the transaction may succeed if it is run again
+func Retryable(err error) bool {
+	var mysqlErr *gomysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysql_Deadlock || mysqlErr.Number == mysql_LockWaitTimeout
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == postgres_SerializationFailure || pgErr.Code == postgres_Deadlock
+	}
+
+	return false
+}
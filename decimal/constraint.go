@@ -0,0 +1,166 @@
+package decimal
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrOutOfRange     = fmt.Errorf("out of range")
+	ErrNotMultipleOf  = fmt.Errorf("not a multiple of step")
+	ErrTooManyDigits  = fmt.Errorf("too many digits")
+	ErrNotPositive    = fmt.Errorf("not positive")
+	ErrInvalidDecimal = fmt.Errorf("invalid decimal")
+)
+
+// Clamp returns min if d is less than min and max if d is greater than max
+func (d Decimal) Clamp(min Decimal, max Decimal) Decimal {
+	if d.d.LessThan(min.d) {
+		return min
+	}
+	if d.d.GreaterThan(max.d) {
+		return max
+	}
+
+	return d
+}
+
+// Between min and max are inclusive
+func (d Decimal) Between(min Decimal, max Decimal) bool {
+	return d.d.GreaterThanOrEqual(min.d) && d.d.LessThanOrEqual(max.d)
+}
+
+// IsMultipleOf false is returned if step is zero
+func (d Decimal) IsMultipleOf(step Decimal) bool {
+	if step.d.IsZero() {
+		return false
+	}
+
+	return d.d.Mod(step.d).IsZero()
+}
+
+// Scale the number of decimal places without trailing zeros, e.g. 1.2300 is 2
+func (d Decimal) Scale() int32 {
+	_, exp := normalize(d.d)
+	if exp >= 0 {
+		return 0
+	}
+
+	return int32(-exp)
+}
+
+// Precision the number of digits needed to store d in a SQL DECIMAL column, integer digits plus Scale
+func (d Decimal) Precision() int32 {
+	precision := d.integerDigits() + d.Scale()
+	if precision == 0 {
+		return 1
+	}
+
+	return precision
+}
+
+func (d Decimal) integerDigits() int32 {
+	integer := d.d.Abs().Truncate(0)
+	if integer.IsZero() {
+		return 0
+	}
+
+	return int32(len(integer.String()))
+}
+
+// FitsIn reports whether d can be stored in a SQL DECIMAL(precision, scale) column without rounding
+func (d Decimal) FitsIn(precision int32, scale int32) bool {
+	return d.Scale() <= scale && d.integerDigits() <= precision-scale
+}
+
+// Validator checks values such as order prices and quantities, every violation is reported by Validate
+type Validator struct {
+	name string
+
+	min      *Decimal
+	max      *Decimal
+	step     *Decimal
+	positive bool
+
+	precision int32
+	scale     int32
+}
+
+func NewValidator(name string) *Validator {
+	return &Validator{
+		name: name,
+
+		min:      nil,
+		max:      nil,
+		step:     nil,
+		positive: false,
+
+		precision: -1,
+		scale:     -1,
+	}
+}
+
+func (v *Validator) Min(min Decimal) *Validator {
+	v.min = &min
+	return v
+}
+
+func (v *Validator) Max(max Decimal) *Validator {
+	v.max = &max
+	return v
+}
+
+// Step the tick size, counted from Min if it is set, otherwise from zero
+func (v *Validator) Step(step Decimal) *Validator {
+	v.step = &step
+	return v
+}
+
+func (v *Validator) Positive() *Validator {
+	v.positive = true
+	return v
+}
+
+// Digits the value has to fit in a SQL DECIMAL(precision, scale) column
+func (v *Validator) Digits(precision int32, scale int32) *Validator {
+	v.precision = precision
+	v.scale = scale
+	return v
+}
+
+// Validate returns nil or the joined errors of all violations, each of them wraps one of the Err constants
+func (v *Validator) Validate(d Decimal) error {
+	if d.err != nil {
+		return fmt.Errorf("%s: %w: %w", v.name, ErrInvalidDecimal, d.err)
+	}
+
+	errs := []error{}
+
+	if v.positive && !d.IsPositive() {
+		errs = append(errs, fmt.Errorf("%s %s is %w", v.name, d, ErrNotPositive))
+	}
+
+	if v.min != nil && d.LessThan(*v.min) {
+		errs = append(errs, fmt.Errorf("%s %s is %w, less than minimum %s", v.name, d, ErrOutOfRange, v.min))
+	}
+
+	if v.max != nil && d.GreaterThan(*v.max) {
+		errs = append(errs, fmt.Errorf("%s %s is %w, greater than maximum %s", v.name, d, ErrOutOfRange, v.max))
+	}
+
+	if v.step != nil {
+		offset := d
+		if v.min != nil {
+			offset = d.Sub(*v.min)
+		}
+		if !offset.IsMultipleOf(*v.step) {
+			errs = append(errs, fmt.Errorf("%s %s is %w %s", v.name, d, ErrNotMultipleOf, v.step))
+		}
+	}
+
+	if v.precision >= 0 && !d.FitsIn(v.precision, v.scale) {
+		errs = append(errs, fmt.Errorf("%s %s has %w for DECIMAL(%d, %d)", v.name, d, ErrTooManyDigits, v.precision, v.scale))
+	}
+
+	return errors.Join(errs...)
+}
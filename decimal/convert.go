@@ -0,0 +1,86 @@
+package decimal
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrOverflow      = fmt.Errorf("overflow")
+	ErrPrecisionLoss = fmt.Errorf("precision loss")
+)
+
+// NewFromScaledInt v is an amount in minor units, e.g. NewFromScaledInt(12345, 2) is 123.45
+func NewFromScaledInt(v int64, scale int32) Decimal {
+	return Decimal{
+		d:   decimal.New(v, -scale),
+		err: nil,
+	}
+}
+
+func NewFromBigInt(v *big.Int, exp int32) Decimal {
+	return Decimal{
+		d:   decimal.NewFromBigInt(v, exp),
+		err: nil,
+	}
+}
+
+func NewFromRat(v *big.Rat, precision int32) Decimal {
+	num := decimal.NewFromBigInt(v.Num(), 0)
+	denom := decimal.NewFromBigInt(v.Denom(), 0)
+
+	return Decimal{
+		d:   num.DivRound(denom, precision),
+		err: nil,
+	}
+}
+
+// BigInt the integer part, the fractional part is truncated
+func (d Decimal) BigInt() *big.Int {
+	return d.d.BigInt()
+}
+
+func (d Decimal) Rat() *big.Rat {
+	return d.d.Rat()
+}
+
+// Coefficient the decimal equals Coefficient * 10 ^ Exponent
+func (d Decimal) Coefficient() *big.Int {
+	return d.d.Coefficient()
+}
+
+func (d Decimal) Exponent() int32 {
+	return d.d.Exponent()
+}
+
+// IntPart the integer part, the fractional part is truncated and the result wraps if it does not fit in int64
+func (d Decimal) IntPart() int64 {
+	return d.d.IntPart()
+}
+
+// ToScaledInt converts to minor units, e.g. 123.45 with scale 2 is 12345,
+// ErrPrecisionLoss is returned if the decimal has more than scale decimal places
+func (d Decimal) ToScaledInt(scale int32) (int64, error) {
+	if d.err != nil {
+		return 0, d.err
+	}
+
+	shifted := d.d.Shift(scale)
+	if !shifted.Equal(shifted.Truncate(0)) {
+		return 0, fmt.Errorf("%w: %s has more than %d decimal places", ErrPrecisionLoss, d.d.String(), scale)
+	}
+
+	v := shifted.BigInt()
+	if !v.IsInt64() {
+		return 0, fmt.Errorf("%w: %s with scale %d", ErrOverflow, d.d.String(), scale)
+	}
+
+	return v.Int64(), nil
+}
+
+// Float64Exact the second value reports whether the float64 represents the decimal without loss
+func (d Decimal) Float64Exact() (float64, bool) {
+	return d.d.Float64()
+}
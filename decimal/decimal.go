@@ -65,6 +65,10 @@ func NewFromDecimal128(v primitive.Decimal128) Decimal {
 	}
 }
 
+func (d Decimal) Err() error {
+	return d.err
+}
+
 func (d Decimal) String() string {
 	return d.d.String()
 }
@@ -74,8 +78,21 @@ func (d Decimal) Float64() float64 {
 	return f
 }
 
+// Decimal128 rounds to 34 significant digits, ±Inf is returned if it is out of the Decimal128 range and NaN if d has
+// an error, so bad values are visible where they are stored. Use ToDecimal128 or ToDecimal128Rounded to get the error.
 func (d Decimal) Decimal128() primitive.Decimal128 {
-	d128, _ := primitive.ParseDecimal128(d.d.String())
+	if d.err != nil {
+		return decimal128_NaN
+	}
+
+	d128, err := d.ToDecimal128Rounded()
+	if err != nil {
+		if d.d.IsNegative() {
+			return decimal128_NegInf
+		}
+		return decimal128_PosInf
+	}
+
 	return d128
 }
 
@@ -143,6 +160,14 @@ func (d Decimal) Div(other Decimal) Decimal {
 	}
 }
 
+// DivRound divides and rounds to places decimal places, Div keeps 16 decimal places
+func (d Decimal) DivRound(other Decimal, places int32) Decimal {
+	return Decimal{
+		d:   d.d.DivRound(other.d, places),
+		err: nil,
+	}
+}
+
 func (d Decimal) Mod(other Decimal) Decimal {
 	return Decimal{
 		d:   d.d.Mod(other.d),
@@ -0,0 +1,90 @@
+package decimal
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/shopspring/decimal"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Decimal128Digits the maximum number of significant digits of a Decimal128
+	Decimal128Digits int = 34
+)
+
+var ten = big.NewInt(10)
+
+var (
+	decimal128_PosInf = primitive.NewDecimal128(0x7800000000000000, 0)
+	decimal128_NegInf = primitive.NewDecimal128(0xf800000000000000, 0)
+	decimal128_NaN    = primitive.NewDecimal128(0x7c00000000000000, 0)
+)
+
+// normalize strips the trailing zeros of the coefficient
+func normalize(d decimal.Decimal) (*big.Int, int) {
+	coef := d.Coefficient()
+	exp := int(d.Exponent())
+	if coef.Sign() == 0 {
+		return coef, 0
+	}
+
+	q, r := new(big.Int), new(big.Int)
+	for {
+		q.QuoRem(coef, ten, r)
+		if r.Sign() != 0 {
+			break
+		}
+		coef.Set(q)
+		exp++
+	}
+
+	return coef, exp
+}
+
+func digits(coef *big.Int) int {
+	return len(new(big.Int).Abs(coef).Text(10))
+}
+
+// ToDecimal128 converts exactly, ErrPrecisionLoss is returned if the decimal has more than 34 significant digits
+// and ErrOverflow if the exponent is out of the Decimal128 range
+func (d Decimal) ToDecimal128() (primitive.Decimal128, error) {
+	if d.err != nil {
+		return primitive.Decimal128{}, d.err
+	}
+
+	coef, exp := normalize(d.d)
+	if digits(coef) > Decimal128Digits {
+		return primitive.Decimal128{}, fmt.Errorf("%w: %s has more than %d significant digits", ErrPrecisionLoss, d.d.String(), Decimal128Digits)
+	}
+
+	d128, ok := primitive.ParseDecimal128FromBigInt(coef, exp)
+	if !ok {
+		if exp < primitive.MinDecimal128Exp {
+			return primitive.Decimal128{}, fmt.Errorf("%w: %sE%d is too small for decimal128", ErrPrecisionLoss, coef.String(), exp)
+		}
+
+		return primitive.Decimal128{}, fmt.Errorf("%w: %sE%d is out of decimal128 range", ErrOverflow, coef.String(), exp)
+	}
+
+	return d128, nil
+}
+
+// ToDecimal128Rounded rounds half to even to 34 significant digits before converting,
+// ErrOverflow is returned if the exponent is still out of the Decimal128 range
+func (d Decimal) ToDecimal128Rounded() (primitive.Decimal128, error) {
+	if d.err != nil {
+		return primitive.Decimal128{}, d.err
+	}
+
+	v := d.d
+	coef, exp := normalize(v)
+	if n := digits(coef); n > Decimal128Digits {
+		v = v.RoundBank(int32(Decimal128Digits - n - exp))
+	}
+	if _, exp = normalize(v); exp < primitive.MinDecimal128Exp {
+		v = v.RoundBank(-primitive.MinDecimal128Exp)
+	}
+
+	return Decimal{d: v, err: nil}.ToDecimal128()
+}
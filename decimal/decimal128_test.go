@@ -0,0 +1,104 @@
+package decimal
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDecimal128RoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 10000; i++ {
+		// up to 34 significant digits with any exponent of the Decimal128 range
+		coef := new(big.Int).Rand(r, new(big.Int).Exp(ten, big.NewInt(int64(r.Intn(Decimal128Digits)+1)), nil))
+		if r.Intn(2) == 0 {
+			coef.Neg(coef)
+		}
+		exp := int32(primitive.MinDecimal128Exp + r.Intn(primitive.MaxDecimal128Exp-primitive.MinDecimal128Exp+1))
+		d := NewFromBigInt(coef, exp)
+
+		d128, err := d.ToDecimal128()
+		if err != nil {
+			t.Fatalf("ToDecimal128(%s): %v", d, err)
+		}
+		if back := NewFromDecimal128(d128); !back.Equal(d) {
+			t.Fatalf("NewFromDecimal128(%s.ToDecimal128()) = %s", d, back)
+		}
+		if back := NewFromDecimal128(d.Decimal128()); !back.Equal(d) {
+			t.Fatalf("NewFromDecimal128(%s.Decimal128()) = %s", d, back)
+		}
+	}
+}
+
+func TestToDecimal128(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		rounded string
+		err     error
+		// roundedErr of ToDecimal128Rounded, which rounds the values ToDecimal128 rejects for their digits
+		roundedErr error
+	}{
+		{"34 digits", "1234567890.123456789012345678901234", "1234567890.123456789012345678901234", nil, nil},
+		{"trailing zeros", "1234567890123456789012345678901234000000", "1234567890123456789012345678901234000000", nil, nil},
+		{"35 digits half to even down", "0.12345678901234567890123456789012345", "0.1234567890123456789012345678901234", ErrPrecisionLoss, nil},
+		{"35 digits half to even up", "0.12345678901234567890123456789012335", "0.1234567890123456789012345678901234", ErrPrecisionLoss, nil},
+		{"above half", "0.123456789012345678901234567890123451", "0.1234567890123456789012345678901235", ErrPrecisionLoss, nil},
+		{"negative", "-12345678901234567890123456789012345", "-12345678901234567890123456789012340", ErrPrecisionLoss, nil},
+		{"max exponent", "9999999999999999999999999999999999E6111", "9999999999999999999999999999999999E6111", nil, nil},
+		{"overflow", "1E6145", "", ErrOverflow, ErrOverflow},
+		{"overflow after rounding", "99999999999999999999999999999999999E6111", "", ErrPrecisionLoss, ErrOverflow},
+		{"min exponent", "1E-6176", "1E-6176", nil, nil},
+		{"underflow to zero", "4E-6177", "0", ErrPrecisionLoss, nil},
+		{"underflow half to even", "15E-6177", "2E-6176", ErrPrecisionLoss, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewFromString(tt.value)
+
+			d128, err := d.ToDecimal128()
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("ToDecimal128() error = %v, want %v", err, tt.err)
+			}
+			if err == nil && !NewFromDecimal128(d128).Equal(d) {
+				t.Fatalf("ToDecimal128() = %s, want %s", d128, tt.value)
+			}
+
+			d128, err = d.ToDecimal128Rounded()
+			if !errors.Is(err, tt.roundedErr) {
+				t.Fatalf("ToDecimal128Rounded() error = %v, want %v", err, tt.roundedErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := NewFromDecimal128(d128); !got.Equal(NewFromString(tt.rounded)) {
+				t.Fatalf("ToDecimal128Rounded() = %s, want %s", got, tt.rounded)
+			}
+		})
+	}
+}
+
+func TestDecimal128(t *testing.T) {
+	tests := []struct {
+		name  string
+		value Decimal
+		want  string
+	}{
+		{"rounded", NewFromString("0.12345678901234567890123456789012345"), "0.1234567890123456789012345678901234"},
+		{"overflow", NewFromString("1E6145"), "Infinity"},
+		{"negative overflow", NewFromString("-1E6145"), "-Infinity"},
+		{"underflow", NewFromString("4E-6177"), "0"},
+		{"error", NewFromString("not a number"), "NaN"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.value.Decimal128(); got.String() != tt.want {
+			t.Errorf("%s: Decimal128() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package finance
+
+import (
+	"fmt"
+
+	"github.com/Lee-Chi/go-sdk/decimal"
+)
+
+var (
+	ErrInvalidPeriods   = fmt.Errorf("periods must be positive")
+	ErrInvalidRate      = fmt.Errorf("rate must be greater than -1")
+	ErrInvalidCashFlows = fmt.Errorf("cash flows must contain both positive and negative values")
+	ErrNoConvergence    = fmt.Errorf("no convergence")
+	ErrZeroBase         = fmt.Errorf("base value is zero")
+)
+
+const (
+	// guard extra decimal places kept by the intermediate results
+	guard int32 = 10
+
+	irrMaxIterations int = 100
+)
+
+var (
+	one          = decimal.NewFromInt(1)
+	hundred      = decimal.NewFromInt(100)
+	basisPerUnit = decimal.NewFromInt(10000)
+)
+
+// Calculator rates are per period and expressed as fractions, e.g. 0.05 is 5%,
+// every result is rounded half away from zero to scale decimal places
+type Calculator struct {
+	scale int32
+}
+
+func New(scale int32) *Calculator {
+	return &Calculator{
+		scale: scale,
+	}
+}
+
+func (c *Calculator) Scale() int32 {
+	return c.scale
+}
+
+// pow keeps the decimal places of base as well, so the power of 1 plus a rate smaller than the guard doesn't round to 1
+func (c *Calculator) pow(base decimal.Decimal, periods int64) decimal.Decimal {
+	return base.PowWithPrecision(decimal.NewFromInt(periods), c.scale+guard+base.Scale())
+}
+
+// PeriodicRate converts an annual rate to the rate of one of periodsPerYear periods
+func (c *Calculator) PeriodicRate(annualRate decimal.Decimal, periodsPerYear int64) (decimal.Decimal, error) {
+	if periodsPerYear <= 0 {
+		return decimal.Zero, ErrInvalidPeriods
+	}
+
+	return annualRate.DivRound(decimal.NewFromInt(periodsPerYear), c.scale+guard), nil
+}
+
+// SimpleInterest principal * rate * periods
+func (c *Calculator) SimpleInterest(principal decimal.Decimal, rate decimal.Decimal, periods int64) decimal.Decimal {
+	return principal.Mul(rate).Mul(decimal.NewFromInt(periods)).Round(c.scale)
+}
+
+// CompoundAmount principal * (1 + rate) ^ periods
+func (c *Calculator) CompoundAmount(principal decimal.Decimal, rate decimal.Decimal, periods int64) (decimal.Decimal, error) {
+	if periods < 0 {
+		return decimal.Zero, ErrInvalidPeriods
+	}
+	if rate.LessThanOrEqual(one.Neg()) {
+		return decimal.Zero, ErrInvalidRate
+	}
+
+	return principal.Mul(c.pow(one.Add(rate), periods)).Round(c.scale), nil
+}
+
+// CompoundInterest the interest part of CompoundAmount
+func (c *Calculator) CompoundInterest(principal decimal.Decimal, rate decimal.Decimal, periods int64) (decimal.Decimal, error) {
+	amount, err := c.CompoundAmount(principal, rate, periods)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return amount.Sub(principal.Round(c.scale)), nil
+}
+
+// Payment the fixed installment repaying principal over periods, principal * rate / (1 - (1 + rate) ^ -periods)
+func (c *Calculator) Payment(principal decimal.Decimal, rate decimal.Decimal, periods int64) (decimal.Decimal, error) {
+	if periods <= 0 {
+		return decimal.Zero, ErrInvalidPeriods
+	}
+	if rate.LessThanOrEqual(one.Neg()) {
+		return decimal.Zero, ErrInvalidRate
+	}
+
+	if rate.IsZero() {
+		return principal.DivRound(decimal.NewFromInt(periods), c.scale), nil
+	}
+
+	factor := c.pow(one.Add(rate), periods)
+	payment := principal.Mul(rate).Mul(factor).DivRound(factor.Sub(one), c.scale+guard)
+
+	return payment.Round(c.scale), nil
+}
+
+type Installment struct {
+	Period    int64
+	Payment   decimal.Decimal
+	Interest  decimal.Decimal
+	Principal decimal.Decimal
+	Balance   decimal.Decimal
+}
+
+// Amortization the schedule of an annuity loan, the last payment absorbs the rounding so the balance ends at zero
+func (c *Calculator) Amortization(principal decimal.Decimal, rate decimal.Decimal, periods int64) ([]Installment, error) {
+	payment, err := c.Payment(principal, rate, periods)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := make([]Installment, 0, periods)
+	balance := principal.Round(c.scale)
+	for period := int64(1); period <= periods; period++ {
+		interest := balance.Mul(rate).Round(c.scale)
+		repaid := payment.Sub(interest)
+		if period == periods {
+			repaid = balance
+		}
+		balance = balance.Sub(repaid)
+
+		schedule = append(schedule, Installment{
+			Period:    period,
+			Payment:   interest.Add(repaid),
+			Interest:  interest,
+			Principal: repaid,
+			Balance:   balance,
+		})
+	}
+
+	return schedule, nil
+}
+
+// NPV net present value of cash flows, the first one happens now and the others at the end of each period
+func (c *Calculator) NPV(rate decimal.Decimal, cashFlows []decimal.Decimal) (decimal.Decimal, error) {
+	if rate.LessThanOrEqual(one.Neg()) {
+		return decimal.Zero, ErrInvalidRate
+	}
+
+	return c.npv(rate, cashFlows).Round(c.scale), nil
+}
+
+func (c *Calculator) npv(rate decimal.Decimal, cashFlows []decimal.Decimal) decimal.Decimal {
+	scale := c.scale + guard
+	base := one.Add(rate)
+
+	sum := decimal.Zero
+	discount := one
+	for i, cf := range cashFlows {
+		if i > 0 {
+			discount = discount.Mul(base).Round(scale)
+		}
+		sum = sum.Add(cf.DivRound(discount, scale))
+	}
+
+	return sum
+}
+
+// dnpv the derivative of npv by rate
+func (c *Calculator) dnpv(rate decimal.Decimal, cashFlows []decimal.Decimal) decimal.Decimal {
+	scale := c.scale + guard
+	base := one.Add(rate)
+
+	sum := decimal.Zero
+	discount := base
+	for i, cf := range cashFlows {
+		if i == 0 {
+			continue
+		}
+		discount = discount.Mul(base).Round(scale)
+		sum = sum.Sub(cf.Mul(decimal.NewFromInt(int64(i))).DivRound(discount, scale))
+	}
+
+	return sum
+}
+
+// IRR the rate making NPV zero, found by Newton's method starting from guess
+func (c *Calculator) IRR(cashFlows []decimal.Decimal, guess decimal.Decimal) (decimal.Decimal, error) {
+	positive, negative := false, false
+	for _, cf := range cashFlows {
+		positive = positive || cf.IsPositive()
+		negative = negative || cf.IsNegative()
+	}
+	if !positive || !negative {
+		return decimal.Zero, ErrInvalidCashFlows
+	}
+
+	scale := c.scale + guard
+	tolerance := decimal.NewFromScaledInt(1, c.scale+2)
+
+	rate := guess
+	for i := 0; i < irrMaxIterations; i++ {
+		if rate.LessThanOrEqual(one.Neg()) {
+			break
+		}
+
+		derivative := c.dnpv(rate, cashFlows)
+		if derivative.IsZero() {
+			break
+		}
+
+		next := rate.Sub(c.npv(rate, cashFlows).DivRound(derivative, scale))
+		if next.Sub(rate).Abs().LessThan(tolerance) {
+			return next.Round(c.scale), nil
+		}
+		rate = next
+	}
+
+	return decimal.Zero, ErrNoConvergence
+}
+
+// PercentChange (to - from) / from * 100
+func (c *Calculator) PercentChange(from decimal.Decimal, to decimal.Decimal) (decimal.Decimal, error) {
+	if from.IsZero() {
+		return decimal.Zero, ErrZeroBase
+	}
+
+	return to.Sub(from).Mul(hundred).DivRound(from.Abs(), c.scale), nil
+}
+
+// ToBasisPoints 0.0125 is 125 basis points
+func (c *Calculator) ToBasisPoints(rate decimal.Decimal) decimal.Decimal {
+	return rate.Mul(basisPerUnit).Round(c.scale)
+}
+
+func (c *Calculator) FromBasisPoints(bps decimal.Decimal) decimal.Decimal {
+	return bps.DivRound(basisPerUnit, c.scale)
+}
+
+// FeeTier applies from Threshold (inclusive) up to the threshold of the next tier
+type FeeTier struct {
+	Threshold decimal.Decimal
+	Rate      decimal.Decimal
+}
+
+// TierFee the whole amount is charged with the rate of the highest tier it reaches, tiers must be sorted by threshold
+func (c *Calculator) TierFee(amount decimal.Decimal, tiers []FeeTier) decimal.Decimal {
+	rate := decimal.Zero
+	for _, tier := range tiers {
+		if amount.LessThan(tier.Threshold) {
+			break
+		}
+		rate = tier.Rate
+	}
+
+	return amount.Mul(rate).Round(c.scale)
+}
+
+// ProgressiveFee every part of the amount is charged with the rate of the tier it falls in, tiers must be sorted by threshold
+func (c *Calculator) ProgressiveFee(amount decimal.Decimal, tiers []FeeTier) decimal.Decimal {
+	fee := decimal.Zero
+	for i, tier := range tiers {
+		if amount.LessThanOrEqual(tier.Threshold) {
+			break
+		}
+
+		upper := amount
+		if i+1 < len(tiers) && tiers[i+1].Threshold.LessThan(amount) {
+			upper = tiers[i+1].Threshold
+		}
+		fee = fee.Add(upper.Sub(tier.Threshold).Mul(tier.Rate))
+	}
+
+	return fee.Round(c.scale)
+}
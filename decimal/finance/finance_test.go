@@ -0,0 +1,342 @@
+package finance
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Lee-Chi/go-sdk/decimal"
+)
+
+func decimals(values ...string) []decimal.Decimal {
+	ds := make([]decimal.Decimal, 0, len(values))
+	for _, v := range values {
+		ds = append(ds, decimal.NewFromString(v))
+	}
+
+	return ds
+}
+
+func TestPeriodicRate(t *testing.T) {
+	tests := []struct {
+		name           string
+		annualRate     string
+		periodsPerYear int64
+		want           string
+		err            error
+	}{
+		{"monthly", "0.06", 12, "0.005", nil},
+		{"daily", "0.05", 365, "0.000136986301", nil},
+		{"zero periods", "0.06", 0, "", ErrInvalidPeriods},
+	}
+
+	c := New(2)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.PeriodicRate(decimal.NewFromString(tt.annualRate), tt.periodsPerYear)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			if err == nil && !got.Equal(decimal.NewFromString(tt.want)) {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimpleInterest(t *testing.T) {
+	tests := []struct {
+		principal string
+		rate      string
+		periods   int64
+		want      string
+	}{
+		{"1000", "0.05", 3, "150"},
+		{"1234.56", "0.015", 2, "37.04"},
+		{"1000", "0", 12, "0"},
+	}
+
+	c := New(2)
+	for _, tt := range tests {
+		got := c.SimpleInterest(decimal.NewFromString(tt.principal), decimal.NewFromString(tt.rate), tt.periods)
+		if !got.Equal(decimal.NewFromString(tt.want)) {
+			t.Errorf("SimpleInterest(%s, %s, %d) = %s, want %s", tt.principal, tt.rate, tt.periods, got, tt.want)
+		}
+	}
+}
+
+func TestCompound(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal string
+		rate      string
+		periods   int64
+		amount    string
+		interest  string
+		err       error
+	}{
+		{"10 years at 5%", "1000", "0.05", 10, "1628.89", "628.89", nil},
+		{"1 year monthly at 6%", "10000", "0.005", 12, "10616.78", "616.78", nil},
+		{"zero periods", "1000", "0.05", 0, "1000", "0", nil},
+		{"negative periods", "1000", "0.05", -1, "", "", ErrInvalidPeriods},
+		{"rate of -1", "1000", "-1", 10, "", "", ErrInvalidRate},
+	}
+
+	c := New(2)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, rate := decimal.NewFromString(tt.principal), decimal.NewFromString(tt.rate)
+
+			amount, err := c.CompoundAmount(principal, rate, tt.periods)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("CompoundAmount() error = %v, want %v", err, tt.err)
+			}
+			if err == nil && !amount.Equal(decimal.NewFromString(tt.amount)) {
+				t.Fatalf("CompoundAmount() = %s, want %s", amount, tt.amount)
+			}
+
+			interest, err := c.CompoundInterest(principal, rate, tt.periods)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("CompoundInterest() error = %v, want %v", err, tt.err)
+			}
+			if err == nil && !interest.Equal(decimal.NewFromString(tt.interest)) {
+				t.Fatalf("CompoundInterest() = %s, want %s", interest, tt.interest)
+			}
+		})
+	}
+}
+
+func TestPayment(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal string
+		rate      string
+		periods   int64
+		want      string
+		err       error
+	}{
+		{"30 years at 6%", "100000", "0.005", 360, "599.55", nil},
+		{"30 years at 4.5%", "200000", "0.00375", 360, "1013.37", nil},
+		{"1 year at 12%", "1000", "0.01", 12, "88.85", nil},
+		{"zero rate", "1200", "0", 12, "100", nil},
+		{"rate below the guard places", "1000", "0.00000000000001", 12, "83.33", nil},
+		{"zero periods", "1000", "0.01", 0, "", ErrInvalidPeriods},
+		{"rate of -1", "1000", "-1", 12, "", ErrInvalidRate},
+	}
+
+	c := New(2)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.Payment(decimal.NewFromString(tt.principal), decimal.NewFromString(tt.rate), tt.periods)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			if err == nil && !got.Equal(decimal.NewFromString(tt.want)) {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAmortization(t *testing.T) {
+	c := New(2)
+
+	schedule, err := c.Amortization(decimal.NewFromInt(1000), decimal.NewFromString("0.01"), 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schedule) != 12 {
+		t.Fatalf("%d installments, want 12", len(schedule))
+	}
+
+	tests := []struct {
+		period    int64
+		payment   string
+		interest  string
+		principal string
+		balance   string
+	}{
+		{1, "88.85", "10", "78.85", "921.15"},
+		{2, "88.85", "9.21", "79.64", "841.51"},
+		{6, "88.85", "5.98", "82.87", "514.92"},
+		{11, "88.85", "1.75", "87.1", "87.96"},
+		// the last payment absorbs the rounding of the others
+		{12, "88.84", "0.88", "87.96", "0"},
+	}
+
+	for _, tt := range tests {
+		got := schedule[tt.period-1]
+		if got.Period != tt.period ||
+			!got.Payment.Equal(decimal.NewFromString(tt.payment)) ||
+			!got.Interest.Equal(decimal.NewFromString(tt.interest)) ||
+			!got.Principal.Equal(decimal.NewFromString(tt.principal)) ||
+			!got.Balance.Equal(decimal.NewFromString(tt.balance)) {
+			t.Errorf("period %d = {%d %s %s %s %s}, want {%d %s %s %s %s}", tt.period,
+				got.Period, got.Payment, got.Interest, got.Principal, got.Balance,
+				tt.period, tt.payment, tt.interest, tt.principal, tt.balance)
+		}
+	}
+
+	repaid := decimal.Zero
+	for _, installment := range schedule {
+		repaid = repaid.Add(installment.Principal)
+	}
+	if !repaid.Equal(decimal.NewFromInt(1000)) {
+		t.Errorf("principal repaid %s, want 1000", repaid)
+	}
+	if last := schedule[len(schedule)-1].Balance; !last.IsZero() {
+		t.Errorf("final balance %s, want 0", last)
+	}
+}
+
+func TestNPV(t *testing.T) {
+	tests := []struct {
+		name      string
+		rate      string
+		cashFlows []string
+		want      string
+		err       error
+	}{
+		{"10%", "0.1", []string{"-10000", "3000", "4200", "6800"}, "1307.29", nil},
+		{"zero rate", "0", []string{"-100", "40", "70"}, "10", nil},
+		{"break even", "0.1", []string{"-100", "110"}, "0", nil},
+		{"no cash flows", "0.1", nil, "0", nil},
+		{"rate of -1", "-1", []string{"-100", "110"}, "", ErrInvalidRate},
+	}
+
+	c := New(2)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.NPV(decimal.NewFromString(tt.rate), decimals(tt.cashFlows...))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			if err == nil && !got.Equal(decimal.NewFromString(tt.want)) {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIRR(t *testing.T) {
+	tests := []struct {
+		name      string
+		cashFlows []string
+		want      string
+		err       error
+	}{
+		{"5 years", []string{"-70000", "12000", "15000", "18000", "21000", "26000"}, "0.0866", nil},
+		{"1 period", []string{"-100", "110"}, "0.1", nil},
+		{"only outflows", []string{"-100", "-10"}, "", ErrInvalidCashFlows},
+		{"only inflows", []string{"100", "10"}, "", ErrInvalidCashFlows},
+	}
+
+	c := New(4)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.IRR(decimals(tt.cashFlows...), decimal.NewFromString("0.1"))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			if err == nil && !got.Equal(decimal.NewFromString(tt.want)) {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFees(t *testing.T) {
+	tiers := []FeeTier{
+		{Threshold: decimal.NewFromInt(0), Rate: decimal.NewFromString("0.01")},
+		{Threshold: decimal.NewFromInt(1000), Rate: decimal.NewFromString("0.005")},
+		{Threshold: decimal.NewFromInt(10000), Rate: decimal.NewFromString("0.0025")},
+	}
+
+	tests := []struct {
+		amount      string
+		tier        string
+		progressive string
+	}{
+		{"0", "0", "0"},
+		{"500", "5", "5"},
+		{"999.99", "10", "10"},
+		{"1000", "5", "10"},
+		{"5000", "25", "30"},
+		{"10000", "25", "55"},
+		{"20000", "50", "80"},
+		{"12345.67", "30.86", "60.86"},
+	}
+
+	c := New(2)
+	for _, tt := range tests {
+		amount := decimal.NewFromString(tt.amount)
+		if got := c.TierFee(amount, tiers); !got.Equal(decimal.NewFromString(tt.tier)) {
+			t.Errorf("TierFee(%s) = %s, want %s", tt.amount, got, tt.tier)
+		}
+		if got := c.ProgressiveFee(amount, tiers); !got.Equal(decimal.NewFromString(tt.progressive)) {
+			t.Errorf("ProgressiveFee(%s) = %s, want %s", tt.amount, got, tt.progressive)
+		}
+	}
+}
+
+func TestAmortizationRateBelowGuard(t *testing.T) {
+	schedule, err := New(2).Amortization(decimal.NewFromInt(1000), decimal.NewFromString("0.00000000000001"), 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first := schedule[0]; !first.Payment.Equal(decimal.NewFromString("83.33")) {
+		t.Errorf("first payment %s, want 83.33", first.Payment)
+	}
+	if last := schedule[len(schedule)-1]; !last.Payment.Equal(decimal.NewFromString("83.37")) || !last.Balance.IsZero() {
+		t.Errorf("last payment %s with balance %s, want 83.37 and 0", last.Payment, last.Balance)
+	}
+}
+
+func TestPercentChange(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		want string
+		err  error
+	}{
+		{"80", "100", "25", nil},
+		{"100", "80", "-20", nil},
+		{"3", "4", "33.33", nil},
+		{"-50", "-25", "50", nil},
+		{"0", "1", "", ErrZeroBase},
+	}
+
+	c := New(2)
+	for _, tt := range tests {
+		got, err := c.PercentChange(decimal.NewFromString(tt.from), decimal.NewFromString(tt.to))
+		if !errors.Is(err, tt.err) {
+			t.Errorf("PercentChange(%s, %s) error = %v, want %v", tt.from, tt.to, err, tt.err)
+			continue
+		}
+		if err == nil && !got.Equal(decimal.NewFromString(tt.want)) {
+			t.Errorf("PercentChange(%s, %s) = %s, want %s", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestBasisPoints(t *testing.T) {
+	tests := []struct {
+		rate string
+		bps  string
+	}{
+		{"0.0125", "125"},
+		{"0.00015", "1.5"},
+		{"-0.01", "-100"},
+		{"0", "0"},
+	}
+
+	c := New(6)
+	for _, tt := range tests {
+		if got := c.ToBasisPoints(decimal.NewFromString(tt.rate)); !got.Equal(decimal.NewFromString(tt.bps)) {
+			t.Errorf("ToBasisPoints(%s) = %s, want %s", tt.rate, got, tt.bps)
+		}
+		if got := c.FromBasisPoints(decimal.NewFromString(tt.bps)); !got.Equal(decimal.NewFromString(tt.rate)) {
+			t.Errorf("FromBasisPoints(%s) = %s, want %s", tt.bps, got, tt.rate)
+		}
+	}
+}
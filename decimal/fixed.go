@@ -0,0 +1,422 @@
+package decimal
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+const (
+	// FixedScale the number of decimal places of Fixed
+	FixedScale int32 = 8
+
+	fixedOne int64 = 100000000
+)
+
+var (
+	ErrDivisionByZero = fmt.Errorf("division by zero")
+)
+
+var (
+	FixedZero Fixed = Fixed{v: 0, err: nil}
+	FixedMax  Fixed = Fixed{v: math.MaxInt64, err: nil}
+	FixedMin  Fixed = Fixed{v: math.MinInt64 + 1, err: nil}
+)
+
+// Fixed is a decimal with FixedScale decimal places stored in an int64, the range is about ±92233720368.
+// Arithmetic never allocates, results are rounded half away from zero and overflow is reported by Err.
+type Fixed struct {
+	v   int64
+	err error
+}
+
+func fixedErrored(err error) Fixed {
+	return Fixed{
+		v:   0,
+		err: err,
+	}
+}
+
+func NewFixedFromInt(v int64) Fixed {
+	if v > math.MaxInt64/fixedOne || v < -math.MaxInt64/fixedOne {
+		return fixedErrored(ErrOverflow)
+	}
+
+	return Fixed{
+		v:   v * fixedOne,
+		err: nil,
+	}
+}
+
+// NewFixedFromScaled v is in units of 10^-FixedScale
+func NewFixedFromScaled(v int64) Fixed {
+	if v == math.MinInt64 {
+		return fixedErrored(ErrOverflow)
+	}
+
+	return Fixed{
+		v:   v,
+		err: nil,
+	}
+}
+
+func NewFixedFromString(v string) Fixed {
+	s := v
+	negative := false
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	integer, fraction, _ := strings.Cut(s, ".")
+	if (integer == "" && fraction == "") || len(fraction) > int(FixedScale) {
+		return fixedErrored(fmt.Errorf("can't convert %s to fixed", v))
+	}
+	fraction += strings.Repeat("0", int(FixedScale)-len(fraction))
+
+	n, err := strconv.ParseUint(integer+fraction, 10, 63)
+	if err != nil {
+		return fixedErrored(fmt.Errorf("can't convert %s to fixed: %w", v, err))
+	}
+
+	if negative {
+		return Fixed{v: -int64(n), err: nil}
+	}
+
+	return Fixed{
+		v:   int64(n),
+		err: nil,
+	}
+}
+
+// NewFixedFromDecimal ErrPrecisionLoss is returned if d has more than FixedScale decimal places
+func NewFixedFromDecimal(d Decimal) Fixed {
+	v, err := d.ToScaledInt(FixedScale)
+	if err != nil {
+		return fixedErrored(err)
+	}
+
+	return NewFixedFromScaled(v)
+}
+
+func (d Decimal) Fixed() Fixed {
+	return NewFixedFromDecimal(d)
+}
+
+func (f Fixed) Decimal() Decimal {
+	if f.err != nil {
+		return errored(f.err)
+	}
+
+	return NewFromScaledInt(f.v, FixedScale)
+}
+
+func (f Fixed) Err() error {
+	return f.err
+}
+
+// Scaled the value in units of 10^-FixedScale
+func (f Fixed) Scaled() int64 {
+	return f.v
+}
+
+func (f Fixed) String() string {
+	u := abs(f.v)
+	s := strconv.FormatUint(u/uint64(fixedOne), 10)
+	if frac := u % uint64(fixedOne); frac != 0 {
+		fs := strconv.FormatUint(frac+uint64(fixedOne), 10)[1:]
+		s += "." + strings.TrimRight(fs, "0")
+	}
+
+	if f.v < 0 {
+		return "-" + s
+	}
+
+	return s
+}
+
+func (f Fixed) Float64() float64 {
+	return float64(f.v) / float64(fixedOne)
+}
+
+func (f Fixed) IsZero() bool {
+	return f.v == 0
+}
+
+func (f Fixed) IsPositive() bool {
+	return f.v > 0
+}
+
+func (f Fixed) IsNegative() bool {
+	return f.v < 0
+}
+
+func (f Fixed) Equal(other Fixed) bool {
+	return f.v == other.v
+}
+
+func (f Fixed) NotEqual(other Fixed) bool {
+	return f.v != other.v
+}
+
+func (f Fixed) GreaterThan(other Fixed) bool {
+	return f.v > other.v
+}
+
+func (f Fixed) GreaterThanOrEqual(other Fixed) bool {
+	return f.v >= other.v
+}
+
+func (f Fixed) LessThan(other Fixed) bool {
+	return f.v < other.v
+}
+
+func (f Fixed) LessThanOrEqual(other Fixed) bool {
+	return f.v <= other.v
+}
+
+func (f Fixed) Sign() int {
+	switch {
+	case f.v > 0:
+		return 1
+	case f.v < 0:
+		return -1
+	}
+
+	return 0
+}
+
+func (f Fixed) Add(other Fixed) Fixed {
+	if f.err != nil {
+		return f
+	}
+	if other.err != nil {
+		return other
+	}
+
+	v := f.v + other.v
+	if (v > f.v) != (other.v > 0) || v == math.MinInt64 {
+		return fixedErrored(ErrOverflow)
+	}
+
+	return Fixed{
+		v:   v,
+		err: nil,
+	}
+}
+
+func (f Fixed) Sub(other Fixed) Fixed {
+	return f.Add(other.Neg())
+}
+
+func (f Fixed) Mul(other Fixed) Fixed {
+	if f.err != nil {
+		return f
+	}
+	if other.err != nil {
+		return other
+	}
+
+	hi, lo := bits.Mul64(abs(f.v), abs(other.v))
+
+	return fixedQuo(hi, lo, uint64(fixedOne), (f.v < 0) != (other.v < 0))
+}
+
+func (f Fixed) Div(other Fixed) Fixed {
+	if f.err != nil {
+		return f
+	}
+	if other.err != nil {
+		return other
+	}
+	if other.v == 0 {
+		return fixedErrored(ErrDivisionByZero)
+	}
+
+	hi, lo := bits.Mul64(abs(f.v), uint64(fixedOne))
+
+	return fixedQuo(hi, lo, abs(other.v), (f.v < 0) != (other.v < 0))
+}
+
+// Pow integer exponents multiply by squaring without allocating, each product rounded to FixedScale places,
+// other exponents are computed by Decimal.PowWithPrecision
+func (f Fixed) Pow(other Fixed) Fixed {
+	if f.err != nil {
+		return f
+	}
+	if other.err != nil {
+		return other
+	}
+
+	if other.v%fixedOne != 0 {
+		return NewFixedFromDecimal(f.Decimal().PowWithPrecision(other.Decimal(), FixedScale))
+	}
+
+	n := other.v / fixedOne
+	if n < 0 {
+		if f.v == 0 {
+			return fixedErrored(ErrDivisionByZero)
+		}
+		return Fixed{v: fixedOne, err: nil}.Div(f.Pow(Fixed{v: -other.v, err: nil}))
+	}
+
+	result := Fixed{v: fixedOne, err: nil}
+	base := f
+	for n > 0 {
+		if n&1 == 1 {
+			result = result.Mul(base)
+		}
+		n >>= 1
+		if n > 0 {
+			base = base.Mul(base)
+		}
+		if result.err != nil || base.err != nil {
+			return fixedErrored(ErrOverflow)
+		}
+	}
+
+	return result
+}
+
+// Mod the result has the sign of f, like Decimal.Mod
+func (f Fixed) Mod(other Fixed) Fixed {
+	if f.err != nil {
+		return f
+	}
+	if other.err != nil {
+		return other
+	}
+	if other.v == 0 {
+		return fixedErrored(ErrDivisionByZero)
+	}
+
+	return Fixed{
+		v:   f.v % other.v,
+		err: nil,
+	}
+}
+
+func (f Fixed) Neg() Fixed {
+	return Fixed{
+		v:   -f.v,
+		err: f.err,
+	}
+}
+
+func (f Fixed) Abs() Fixed {
+	if f.v < 0 {
+		return f.Neg()
+	}
+
+	return f
+}
+
+// Round rounds half away from zero to places decimal places, places must be between 0 and FixedScale
+func (f Fixed) Round(places int32) Fixed {
+	unit, ok := fixedUnit(places)
+	if !ok || f.err != nil {
+		return f
+	}
+
+	r := f.v % unit
+	v := f.v - r
+	if abs(r)*2 >= uint64(unit) {
+		if f.v < 0 {
+			return Fixed{v: v, err: nil}.Sub(Fixed{v: unit, err: nil})
+		}
+		return Fixed{v: v, err: nil}.Add(Fixed{v: unit, err: nil})
+	}
+
+	return Fixed{
+		v:   v,
+		err: nil,
+	}
+}
+
+func (f Fixed) Truncate(places int32) Fixed {
+	unit, ok := fixedUnit(places)
+	if !ok || f.err != nil {
+		return f
+	}
+
+	return Fixed{
+		v:   f.v - f.v%unit,
+		err: nil,
+	}
+}
+
+func (f Fixed) Floor() Fixed {
+	if f.err != nil {
+		return f
+	}
+
+	t := f.Truncate(0)
+	if f.v < 0 && t.v != f.v {
+		return t.Sub(Fixed{v: fixedOne, err: nil})
+	}
+
+	return t
+}
+
+func (f Fixed) Ceil() Fixed {
+	if f.err != nil {
+		return f
+	}
+
+	t := f.Truncate(0)
+	if f.v > 0 && t.v != f.v {
+		return t.Add(Fixed{v: fixedOne, err: nil})
+	}
+
+	return t
+}
+
+func fixedUnit(places int32) (int64, bool) {
+	if places < 0 || places > FixedScale {
+		return 0, false
+	}
+
+	unit := int64(1)
+	for i := places; i < FixedScale; i++ {
+		unit *= 10
+	}
+
+	return unit, true
+}
+
+// fixedQuo divides the 128-bit hi:lo by d rounding half away from zero
+func fixedQuo(hi uint64, lo uint64, d uint64, negative bool) Fixed {
+	if hi >= d {
+		return fixedErrored(ErrOverflow)
+	}
+
+	q, r := bits.Div64(hi, lo, d)
+	if q > math.MaxInt64 {
+		return fixedErrored(ErrOverflow)
+	}
+	if r >= d-r {
+		q++
+	}
+	if q > math.MaxInt64 {
+		return fixedErrored(ErrOverflow)
+	}
+
+	if negative {
+		return Fixed{v: -int64(q), err: nil}
+	}
+
+	return Fixed{
+		v:   int64(q),
+		err: nil,
+	}
+}
+
+func abs(v int64) uint64 {
+	if v < 0 {
+		return uint64(-v)
+	}
+
+	return uint64(v)
+}
@@ -0,0 +1,144 @@
+package decimal
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestNewFixedFromInt(t *testing.T) {
+	limit := int64(math.MaxInt64 / fixedOne)
+
+	tests := []struct {
+		v   int64
+		err error
+	}{
+		{0, nil},
+		{limit, nil},
+		{-limit, nil},
+		{limit + 1, ErrOverflow},
+		{-limit - 1, ErrOverflow},
+		{math.MaxInt64, ErrOverflow},
+		{math.MinInt64, ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		f := NewFixedFromInt(tt.v)
+		if !errors.Is(f.Err(), tt.err) {
+			t.Errorf("NewFixedFromInt(%d) error = %v, want %v", tt.v, f.Err(), tt.err)
+		}
+		if tt.err == nil && f.Scaled() != tt.v*fixedOne {
+			t.Errorf("NewFixedFromInt(%d) = %s", tt.v, f)
+		}
+	}
+}
+
+func TestFixedArithmetic(t *testing.T) {
+	unit := NewFixedFromScaled(1)
+
+	tests := []struct {
+		name string
+		got  Fixed
+		want string
+		err  error
+	}{
+		{"add", NewFixedFromString("1.5").Add(NewFixedFromString("2.25")), "3.75", nil},
+		{"add to max", FixedMax.Sub(unit).Add(unit), FixedMax.String(), nil},
+		{"add over max", FixedMax.Add(unit), "", ErrOverflow},
+		{"sub under min", FixedMin.Sub(unit), "", ErrOverflow},
+		{"add max to max", FixedMax.Add(FixedMax), "", ErrOverflow},
+		{"add min to min", FixedMin.Add(FixedMin), "", ErrOverflow},
+		{"mul", NewFixedFromString("1.5").Mul(NewFixedFromString("-2.25")), "-3.375", nil},
+		{"mul rounds half away from zero", unit.Mul(NewFixedFromString("0.5")), "0.00000001", nil},
+		{"mul rounds negative half away from zero", unit.Neg().Mul(NewFixedFromString("0.5")), "-0.00000001", nil},
+		{"mul overflow", FixedMax.Mul(FixedMax), "", ErrOverflow},
+		{"mul overflow by rounding", FixedMax.Mul(NewFixedFromString("1.00000001")), "", ErrOverflow},
+		{"div", NewFixedFromInt(1).Div(NewFixedFromInt(3)), "0.33333333", nil},
+		{"div rounds", NewFixedFromInt(2).Div(NewFixedFromInt(3)), "0.66666667", nil},
+		{"div overflow", FixedMax.Div(unit), "", ErrOverflow},
+		{"div by zero", NewFixedFromInt(1).Div(FixedZero), "", ErrDivisionByZero},
+		{"mod", NewFixedFromString("-7.5").Mod(NewFixedFromInt(2)), "-1.5", nil},
+		{"pow", NewFixedFromString("1.5").Pow(NewFixedFromInt(3)), "3.375", nil},
+		{"pow zero", NewFixedFromString("1.5").Pow(FixedZero), "1", nil},
+		{"pow negative", NewFixedFromInt(2).Pow(NewFixedFromInt(-2)), "0.25", nil},
+		{"pow fraction", NewFixedFromInt(2).Pow(NewFixedFromString("0.5")), "1.41421356", nil},
+		{"pow overflow", NewFixedFromInt(10).Pow(NewFixedFromInt(11)), "", ErrOverflow},
+		{"pow zero to negative", FixedZero.Pow(NewFixedFromInt(-1)), "", ErrDivisionByZero},
+		{"round", NewFixedFromString("-2.345").Round(2), "-2.35", nil},
+		{"round to max", FixedMax.Round(0), "", ErrOverflow},
+		{"floor", NewFixedFromString("-2.1").Floor(), "-3", nil},
+		{"ceil", NewFixedFromString("2.1").Ceil(), "3", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.got.Err(), tt.err) {
+				t.Fatalf("error = %v, want %v", tt.got.Err(), tt.err)
+			}
+			if tt.err == nil && tt.got.String() != tt.want {
+				t.Fatalf("got %s, want %s", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFixedQuoOverflow(t *testing.T) {
+	tests := []struct {
+		name string
+		hi   uint64
+		lo   uint64
+		d    uint64
+		err  error
+	}{
+		{"hi equal to d", 5, 0, 5, ErrOverflow},
+		{"hi above d", 6, 0, 5, ErrOverflow},
+		{"quotient above MaxInt64", 0, math.MaxUint64, 1, ErrOverflow},
+		{"MaxInt64", 0, math.MaxInt64, 1, nil},
+		{"rounded above MaxInt64", 0, math.MaxInt64*2 + 1, 2, ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		if f := fixedQuo(tt.hi, tt.lo, tt.d, false); !errors.Is(f.Err(), tt.err) {
+			t.Errorf("%s: error = %v, want %v", tt.name, f.Err(), tt.err)
+		}
+	}
+}
+
+func TestFixedDecimal(t *testing.T) {
+	for _, s := range []string{"0", "1", "-1", "0.00000001", "92233720368.54775807", "-92233720368.54775807"} {
+		f := NewFixedFromString(s)
+		if f.Err() != nil {
+			t.Fatalf("NewFixedFromString(%s): %v", s, f.Err())
+		}
+		if d := f.Decimal(); d.String() != s || !d.Fixed().Equal(f) {
+			t.Errorf("%s round-trips through Decimal as %s", s, d)
+		}
+	}
+
+	if f := NewFromString("0.000000001").Fixed(); !errors.Is(f.Err(), ErrPrecisionLoss) {
+		t.Errorf("9 decimal places error = %v, want %v", f.Err(), ErrPrecisionLoss)
+	}
+}
+
+var (
+	fixedSink   Fixed
+	decimalSink Decimal
+)
+
+func BenchmarkFixedMul(b *testing.B) {
+	x, y := NewFixedFromString("1234.5678"), NewFixedFromString("0.98765432")
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		fixedSink = x.Mul(y).Add(x)
+	}
+}
+
+func BenchmarkDecimalMul(b *testing.B) {
+	x, y := NewFromString("1234.5678"), NewFromString("0.98765432")
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		decimalSink = x.Mul(y).Round(FixedScale).Add(x)
+	}
+}
@@ -0,0 +1,314 @@
+package decimal
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+)
+
+type Locale struct {
+	Decimal string
+	Group   string
+}
+
+var (
+	Locale_EN Locale = Locale{Decimal: ".", Group: ","}
+	Locale_DE Locale = Locale{Decimal: ",", Group: "."}
+	Locale_FR Locale = Locale{Decimal: ",", Group: " "}
+	Locale_CH Locale = Locale{Decimal: ".", Group: "'"}
+)
+
+var (
+	ErrInvalidPattern = fmt.Errorf("invalid pattern")
+)
+
+// Formatter formats decimals by a pattern such as "#,##0.00", "0.00%" or "0.###E0"
+//
+//	0 required digit
+//	# optional digit
+//	, grouping separator, the group size is the number of digits after the last one
+//	. decimal separator
+//	% multiply by 100 and show the percent sign
+//	E scientific notation, followed by the minimum exponent digits
+//
+// Any other leading or trailing characters are kept as prefix and suffix.
+type Formatter struct {
+	prefix string
+	suffix string
+
+	minInt    int
+	minFrac   int
+	maxFrac   int
+	groupSize int
+
+	percent     bool
+	scientific  bool
+	minExpDigit int
+
+	locale Locale
+	width  int
+	pad    rune
+}
+
+func NewFormatter(pattern string) (*Formatter, error) {
+	f := &Formatter{
+		locale: Locale_EN,
+		width:  0,
+		pad:    ' ',
+	}
+
+	start := strings.IndexAny(pattern, "#0,.")
+	if start < 0 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidPattern, pattern)
+	}
+	f.prefix = pattern[:start]
+
+	end := start
+	for end < len(pattern) && strings.IndexByte("#0,.", pattern[end]) >= 0 {
+		end++
+	}
+	number := pattern[start:end]
+	rest := pattern[end:]
+
+	if strings.HasPrefix(rest, "E") {
+		rest = rest[1:]
+		digits := 0
+		for digits < len(rest) && rest[digits] == '0' {
+			digits++
+		}
+		if digits == 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidPattern, pattern)
+		}
+		f.scientific = true
+		f.minExpDigit = digits
+		rest = rest[digits:]
+	}
+
+	f.suffix = rest
+	if strings.Contains(rest, "%") {
+		f.percent = true
+	}
+
+	integer, fraction, hasDot := strings.Cut(number, ".")
+	if hasDot && strings.ContainsAny(fraction, ".,") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidPattern, pattern)
+	}
+
+	if i := strings.LastIndexByte(integer, ','); i >= 0 {
+		f.groupSize = len(integer) - i - 1
+		if f.groupSize == 0 || f.scientific {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidPattern, pattern)
+		}
+	}
+
+	for _, c := range strings.ReplaceAll(integer, ",", "") {
+		if c == '0' {
+			f.minInt++
+			continue
+		}
+		if f.minInt > 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidPattern, pattern)
+		}
+	}
+
+	optional := false
+	for _, c := range fraction {
+		if c == '0' {
+			if optional {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidPattern, pattern)
+			}
+			f.minFrac++
+		} else {
+			optional = true
+		}
+		f.maxFrac++
+	}
+
+	if f.scientific && f.minInt == 0 {
+		f.minInt = 1
+	}
+
+	return f, nil
+}
+
+func (f *Formatter) Locale(locale Locale) *Formatter {
+	f.locale = locale
+	return f
+}
+
+// Width pads the formatted string on the left with pad until it is width runes long
+func (f *Formatter) Width(width int, pad rune) *Formatter {
+	f.width = width
+	f.pad = pad
+	return f
+}
+
+func (f *Formatter) Format(d Decimal) string {
+	v := d.d
+	if f.percent {
+		v = v.Shift(2)
+	}
+
+	exponent := ""
+	if f.scientific {
+		var exp int64
+		v, exp = f.normalize(v)
+
+		sign := ""
+		if exp < 0 {
+			sign = "-"
+			exp = -exp
+		}
+		exponent = fmt.Sprintf("E%s%0*d", sign, f.minExpDigit, exp)
+	}
+
+	v = v.Round(int32(f.maxFrac))
+	negative := v.IsNegative()
+
+	integer, fraction, _ := strings.Cut(v.Abs().StringFixed(int32(f.maxFrac)), ".")
+
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) < f.minFrac {
+		fraction += strings.Repeat("0", f.minFrac-len(fraction))
+	}
+
+	if integer == "0" && f.minInt == 0 {
+		integer = ""
+	}
+	if len(integer) < f.minInt {
+		integer = strings.Repeat("0", f.minInt-len(integer)) + integer
+	}
+	if integer == "" && fraction == "" {
+		integer = "0"
+	}
+
+	if f.groupSize > 0 {
+		integer = group(integer, f.groupSize, f.locale.Group)
+	}
+
+	var b strings.Builder
+	if negative {
+		b.WriteString("-")
+	}
+	b.WriteString(f.prefix)
+	b.WriteString(integer)
+	if fraction != "" {
+		b.WriteString(f.locale.Decimal)
+		b.WriteString(fraction)
+	}
+	b.WriteString(exponent)
+	b.WriteString(f.suffix)
+
+	s := b.String()
+	if n := utf8.RuneCountInString(s); n < f.width {
+		s = strings.Repeat(string(f.pad), f.width-n) + s
+	}
+
+	return s
+}
+
+// normalize shifts v so that it has exactly minInt integer digits and returns the shifted exponent
+func (f *Formatter) normalize(v decimal.Decimal) (decimal.Decimal, int64) {
+	if v.IsZero() {
+		return v, 0
+	}
+
+	digits := int64(len(v.Coefficient().Text(10)))
+	if v.IsNegative() {
+		digits--
+	}
+
+	exp := digits + int64(v.Exponent()) - int64(f.minInt)
+	m := v.Shift(int32(-exp))
+
+	// rounding may carry into a new integer digit, e.g. 9.999 -> 10.00
+	if m.Abs().Round(int32(f.maxFrac)).GreaterThanOrEqual(decimal.New(1, int32(f.minInt))) {
+		exp++
+		m = v.Shift(int32(-exp))
+	}
+
+	return m, exp
+}
+
+func group(integer string, size int, sep string) string {
+	if len(integer) <= size {
+		return integer
+	}
+
+	var b strings.Builder
+	head := len(integer) % size
+	if head > 0 {
+		b.WriteString(integer[:head])
+	}
+	for i := head; i < len(integer); i += size {
+		if b.Len() > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(integer[i : i+size])
+	}
+
+	return b.String()
+}
+
+// Format formats the decimal by the pattern with Locale_EN, the plain string is returned if the pattern is invalid
+func (d Decimal) Format(pattern string) string {
+	f, err := NewFormatter(pattern)
+	if err != nil {
+		return d.String()
+	}
+
+	return f.Format(d)
+}
+
+func (d Decimal) FormatLocale(pattern string, locale Locale) string {
+	f, err := NewFormatter(pattern)
+	if err != nil {
+		return d.String()
+	}
+
+	return f.Locale(locale).Format(d)
+}
+
+// ParseLocalized parses strings like "1.234,56", "12,5 %" or "1,2E3" written with the given locale
+func ParseLocalized(v string, locale Locale) Decimal {
+	s := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\u00a0', '\u202f':
+			return -1
+		}
+		return r
+	}, v)
+
+	percent := false
+	if strings.HasSuffix(s, "%") {
+		percent = true
+		s = strings.TrimSuffix(s, "%")
+	}
+
+	if group := strings.TrimSpace(locale.Group); group != "" && group != locale.Decimal {
+		s = strings.ReplaceAll(s, group, "")
+	}
+	if locale.Decimal != "." {
+		s = strings.ReplaceAll(s, locale.Decimal, ".")
+	}
+	s = strings.TrimPrefix(s, "+")
+
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return Decimal{
+			d:   decimal.Zero,
+			err: err,
+		}
+	}
+
+	if percent {
+		d = d.Shift(-2)
+	}
+
+	return Decimal{
+		d:   d,
+		err: nil,
+	}
+}
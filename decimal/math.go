@@ -0,0 +1,246 @@
+package decimal
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrDomain = fmt.Errorf("argument out of domain")
+)
+
+const (
+	// guardDigits extra decimal places kept by the intermediate results
+	guardDigits int32 = 10
+
+	// maxExactPow the largest integer exponent computed exactly by PowWithPrecision
+	maxExactPow int64 = 1000
+)
+
+var (
+	one = decimal.NewFromInt(1)
+	two = decimal.NewFromInt(2)
+)
+
+// Sqrt square root rounded to precision decimal places, ErrDomain is returned for negative values
+func (d Decimal) Sqrt(precision int32) Decimal {
+	if d.err != nil {
+		return d
+	}
+	if d.d.IsNegative() {
+		return errored(fmt.Errorf("%w: sqrt of %s", ErrDomain, d.d.String()))
+	}
+
+	return Decimal{
+		d:   sqrt(d.d, precision),
+		err: nil,
+	}
+}
+
+// Exp e to the power of d rounded to precision decimal places
+func (d Decimal) Exp(precision int32) Decimal {
+	if d.err != nil {
+		return d
+	}
+
+	return Decimal{
+		d:   exp(d.d, precision+guardDigits).Round(precision),
+		err: nil,
+	}
+}
+
+// Ln natural logarithm rounded to precision decimal places, ErrDomain is returned for values not greater than zero
+func (d Decimal) Ln(precision int32) Decimal {
+	if d.err != nil {
+		return d
+	}
+	if d.d.Sign() <= 0 {
+		return errored(fmt.Errorf("%w: ln of %s", ErrDomain, d.d.String()))
+	}
+
+	return Decimal{
+		d:   ln(d.d, precision+guardDigits).Round(precision),
+		err: nil,
+	}
+}
+
+// Log10 base 10 logarithm rounded to precision decimal places, ErrDomain is returned for values not greater than zero
+func (d Decimal) Log10(precision int32) Decimal {
+	if d.err != nil {
+		return d
+	}
+	if d.d.Sign() <= 0 {
+		return errored(fmt.Errorf("%w: log10 of %s", ErrDomain, d.d.String()))
+	}
+
+	scale := precision + guardDigits
+
+	return Decimal{
+		d:   ln(d.d, scale).DivRound(ln(decimal.NewFromInt(10), scale), scale).Round(precision),
+		err: nil,
+	}
+}
+
+// PowWithPrecision d to the power of other rounded to precision decimal places, other may be fractional.
+// ErrDomain is returned for a negative base with a fractional exponent and for zero to a negative power.
+func (d Decimal) PowWithPrecision(other Decimal, precision int32) Decimal {
+	if d.err != nil {
+		return d
+	}
+	if other.err != nil {
+		return other
+	}
+
+	base, e := d.d, other.d
+	scale := precision + guardDigits
+	integer := e.Equal(e.Truncate(0))
+
+	if base.IsZero() {
+		if e.IsNegative() {
+			return errored(fmt.Errorf("%w: 0 to the power of %s", ErrDomain, e.String()))
+		}
+		if e.IsZero() {
+			return Decimal{d: one, err: nil}
+		}
+		return Decimal{d: decimal.Zero, err: nil}
+	}
+
+	// small integer powers are exact by repeated multiplication
+	if integer && e.Abs().LessThanOrEqual(decimal.NewFromInt(maxExactPow)) {
+		p := base.Pow(e.Abs())
+		if e.IsNegative() {
+			p = one.DivRound(p, scale)
+		}
+		return Decimal{
+			d:   p.Round(precision),
+			err: nil,
+		}
+	}
+
+	if base.IsNegative() && !integer {
+		return errored(fmt.Errorf("%w: %s to the power of %s", ErrDomain, base.String(), e.String()))
+	}
+
+	// the error of ln is magnified by the exponent and by the size of the result, so keep more digits for it
+	t := e.Mul(ln(base.Abs(), guardDigits))
+	lnScale := scale + int32(len(e.Abs().Truncate(0).String())) + int32(max(0, t.IntPart()*434/1000))
+
+	p := exp(e.Mul(ln(base.Abs(), lnScale)), scale)
+	if base.IsNegative() && !e.Mod(two).IsZero() {
+		p = p.Neg()
+	}
+
+	return Decimal{
+		d:   p.Round(precision),
+		err: nil,
+	}
+}
+
+// sqrt Newton's method rounded to precision decimal places, v must not be negative
+func sqrt(v decimal.Decimal, precision int32) decimal.Decimal {
+	if v.IsZero() {
+		return decimal.Zero
+	}
+
+	scale := precision + 2
+	// v is below 10^magnitude, so 10^ceil(magnitude/2) is above its root whatever its size, from above Newton's
+	// method decreases until the rounding stops it
+	magnitude := len(v.Coefficient().String()) + int(v.Exponent())
+	x := decimal.New(1, int32(math.Ceil(float64(magnitude)/2)))
+
+	for {
+		next := x.Add(v.DivRound(x, scale)).DivRound(two, scale)
+		if next.IsZero() {
+			// the root is below the last place of scale
+			return decimal.Zero
+		}
+		if next.GreaterThanOrEqual(x) {
+			break
+		}
+		x = next
+	}
+
+	return x.Round(precision)
+}
+
+// exp halves x until it is below 0.5, sums the Taylor series and squares the result back
+func exp(x decimal.Decimal, scale int32) decimal.Decimal {
+	if x.IsZero() {
+		return one
+	}
+	if x.IsNegative() {
+		return one.DivRound(exp(x.Neg(), scale+int32(len(x.Truncate(0).String()))), scale)
+	}
+
+	half := decimal.NewFromFloat(0.5)
+	k := 0
+	r := x
+	for r.GreaterThan(half) {
+		r = r.DivRound(two, scale+int32(k))
+		k++
+	}
+
+	// squaring k times doubles the relative error each time, and the result has up to x/ln10 integer digits
+	wp := scale + int32(k) + int32(x.IntPart()*434/1000) + 2
+
+	sum := one
+	term := one
+	for i := int64(1); ; i++ {
+		term = term.Mul(r).DivRound(decimal.NewFromInt(i), wp)
+		if term.IsZero() {
+			break
+		}
+		sum = sum.Add(term)
+	}
+
+	for ; k > 0; k-- {
+		sum = sum.Mul(sum).Round(wp)
+	}
+
+	return sum.Round(scale)
+}
+
+// ln writes x as m * 2^j * 10^e with m in [1, 2) and sums 2 * atanh((m-1)/(m+1)) for ln(m)
+func ln(x decimal.Decimal, scale int32) decimal.Decimal {
+	coef := x.Coefficient().String()
+	e := int64(x.Exponent()) + int64(len(coef)) - 1
+	m := x.Shift(int32(-e))
+
+	wp := scale + int32(len(fmt.Sprint(e))) + 2
+
+	j := int64(0)
+	for m.GreaterThanOrEqual(two) {
+		m = m.DivRound(two, wp)
+		j++
+	}
+
+	ln2 := atanh2(one.DivRound(decimal.NewFromInt(3), wp), wp)
+	ln10 := ln2.Mul(decimal.NewFromInt(3)).Add(atanh2(one.DivRound(decimal.NewFromInt(9), wp), wp))
+
+	z := m.Sub(one).DivRound(m.Add(one), wp)
+	result := atanh2(z, wp).
+		Add(ln2.Mul(decimal.NewFromInt(j))).
+		Add(ln10.Mul(decimal.NewFromInt(e)))
+
+	return result.Round(scale)
+}
+
+// atanh2 2 * atanh(z) = ln((1+z)/(1-z)) for |z| < 1
+func atanh2(z decimal.Decimal, scale int32) decimal.Decimal {
+	z2 := z.Mul(z).Round(scale)
+
+	sum := z
+	power := z
+	for i := int64(3); ; i += 2 {
+		power = power.Mul(z2).Round(scale)
+		term := power.DivRound(decimal.NewFromInt(i), scale)
+		if term.IsZero() {
+			break
+		}
+		sum = sum.Add(term)
+	}
+
+	return sum.Mul(two)
+}
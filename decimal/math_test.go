@@ -0,0 +1,49 @@
+package decimal
+
+import (
+	"testing"
+)
+
+func TestSqrt(t *testing.T) {
+	tests := []struct {
+		value     string
+		precision int32
+		want      string
+	}{
+		{"0", 2, "0"},
+		{"2", 10, "1.4142135624"},
+		{"0.0001", 4, "0.01"},
+		{"1e400", 2, "1e200"},
+		{"2e401", 0, "447213595499957939281834733746255247088123671922305144854179449082104185127560979882882881675756454993901635230154756700850653544889414772717272024306690541773355634638375833162255329064527971316107152"},
+		{"1e-400", 210, "1e-200"},
+		{"1e-400", 2, "0"},
+		{"12345678901234567890", 5, "3513641828.82014"},
+	}
+
+	for _, tt := range tests {
+		if got := NewFromString(tt.value).Sqrt(tt.precision); !got.Equal(NewFromString(tt.want)) {
+			t.Errorf("Sqrt(%s, %d) = %s, want %s", tt.value, tt.precision, got, tt.want)
+		}
+	}
+}
+
+func TestStdDevMagnitude(t *testing.T) {
+	tests := []struct {
+		values []string
+		want   string
+	}{
+		{[]string{"1e200", "-1e200"}, "1e200"},
+		{[]string{"1e-200", "-1e-200"}, "1e-200"},
+	}
+
+	for _, tt := range tests {
+		values := make([]Decimal, 0, len(tt.values))
+		for _, v := range tt.values {
+			values = append(values, NewFromString(v))
+		}
+
+		if got := StdDev(values, 210); !got.Equal(NewFromString(tt.want)) {
+			t.Errorf("StdDev(%v) = %s, want %s", tt.values, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package decimal
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrEmpty          = fmt.Errorf("empty values")
+	ErrLengthMismatch = fmt.Errorf("length mismatch")
+	ErrZeroWeight     = fmt.Errorf("total weight is zero")
+)
+
+func errored(err error) Decimal {
+	return Decimal{
+		d:   decimal.Zero,
+		err: err,
+	}
+}
+
+// firstErr returns the first error state found in values
+func firstErr(values []Decimal) error {
+	for _, v := range values {
+		if v.err != nil {
+			return v.err
+		}
+	}
+
+	return nil
+}
+
+func Sum(values ...Decimal) Decimal {
+	if err := firstErr(values); err != nil {
+		return errored(err)
+	}
+
+	sum := decimal.Zero
+	for _, v := range values {
+		sum = sum.Add(v.d)
+	}
+
+	return Decimal{
+		d:   sum,
+		err: nil,
+	}
+}
+
+func Avg(values ...Decimal) Decimal {
+	if len(values) == 0 {
+		return errored(ErrEmpty)
+	}
+
+	sum := Sum(values...)
+	if sum.err != nil {
+		return sum
+	}
+
+	return Decimal{
+		d:   sum.d.Div(decimal.NewFromInt(int64(len(values)))),
+		err: nil,
+	}
+}
+
+func Min(values ...Decimal) Decimal {
+	if len(values) == 0 {
+		return errored(ErrEmpty)
+	}
+	if err := firstErr(values); err != nil {
+		return errored(err)
+	}
+
+	min := values[0]
+	for _, v := range values[1:] {
+		if v.d.LessThan(min.d) {
+			min = v
+		}
+	}
+
+	return min
+}
+
+func Max(values ...Decimal) Decimal {
+	if len(values) == 0 {
+		return errored(ErrEmpty)
+	}
+	if err := firstErr(values); err != nil {
+		return errored(err)
+	}
+
+	max := values[0]
+	for _, v := range values[1:] {
+		if v.d.GreaterThan(max.d) {
+			max = v
+		}
+	}
+
+	return max
+}
+
+// Median the mean of the two middle values is returned when the count is even
+func Median(values ...Decimal) Decimal {
+	if len(values) == 0 {
+		return errored(ErrEmpty)
+	}
+	if err := firstErr(values); err != nil {
+		return errored(err)
+	}
+
+	sorted := make([]decimal.Decimal, len(values))
+	for i, v := range values {
+		sorted[i] = v.d
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].LessThan(sorted[j])
+	})
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return Decimal{
+			d:   sorted[mid],
+			err: nil,
+		}
+	}
+
+	return Decimal{
+		d:   sorted[mid-1].Add(sorted[mid]).Div(decimal.NewFromInt(2)),
+		err: nil,
+	}
+}
+
+func WeightedAvg(values []Decimal, weights []Decimal) Decimal {
+	if len(values) == 0 {
+		return errored(ErrEmpty)
+	}
+	if len(values) != len(weights) {
+		return errored(ErrLengthMismatch)
+	}
+	if err := firstErr(values); err != nil {
+		return errored(err)
+	}
+	if err := firstErr(weights); err != nil {
+		return errored(err)
+	}
+
+	sum := decimal.Zero
+	total := decimal.Zero
+	for i := range values {
+		sum = sum.Add(values[i].d.Mul(weights[i].d))
+		total = total.Add(weights[i].d)
+	}
+
+	if total.IsZero() {
+		return errored(ErrZeroWeight)
+	}
+
+	return Decimal{
+		d:   sum.Div(total),
+		err: nil,
+	}
+}
+
+// Variance population variance rounded to precision decimal places
+func Variance(values []Decimal, precision int32) Decimal {
+	return variance(values, 0, precision)
+}
+
+// SampleVariance sample variance (divided by n-1) rounded to precision decimal places
+func SampleVariance(values []Decimal, precision int32) Decimal {
+	return variance(values, 1, precision)
+}
+
+// StdDev population standard deviation rounded to precision decimal places
+func StdDev(values []Decimal, precision int32) Decimal {
+	// the root has about half the digits of the variance, which is kept with twice as many places
+	v := variance(values, 0, 2*precision+guardDigits)
+	if v.err != nil {
+		return v
+	}
+
+	return Decimal{
+		d:   sqrt(v.d, precision),
+		err: nil,
+	}
+}
+
+// SampleStdDev sample standard deviation rounded to precision decimal places
+func SampleStdDev(values []Decimal, precision int32) Decimal {
+	// the root has about half the digits of the variance, which is kept with twice as many places
+	v := variance(values, 1, 2*precision+guardDigits)
+	if v.err != nil {
+		return v
+	}
+
+	return Decimal{
+		d:   sqrt(v.d, precision),
+		err: nil,
+	}
+}
+
+func variance(values []Decimal, ddof int, precision int32) Decimal {
+	if len(values)-ddof <= 0 {
+		return errored(ErrEmpty)
+	}
+	if err := firstErr(values); err != nil {
+		return errored(err)
+	}
+
+	n := decimal.NewFromInt(int64(len(values)))
+
+	sum := decimal.Zero
+	for _, v := range values {
+		sum = sum.Add(v.d)
+	}
+	// keep the mean exact enough so the squared deviations do not lose digits
+	mean := sum.DivRound(n, precision+8)
+
+	squares := decimal.Zero
+	for _, v := range values {
+		diff := v.d.Sub(mean)
+		squares = squares.Add(diff.Mul(diff))
+	}
+
+	return Decimal{
+		d:   squares.DivRound(decimal.NewFromInt(int64(len(values)-ddof)), precision),
+		err: nil,
+	}
+}
+
+// CumSum running totals, every element after the first errored value carries its error
+func CumSum(values ...Decimal) []Decimal {
+	sums := make([]Decimal, len(values))
+
+	sum := Zero
+	for i, v := range values {
+		if sum.err == nil && v.err != nil {
+			sum = errored(v.err)
+		}
+		if sum.err == nil {
+			sum = Decimal{
+				d:   sum.d.Add(v.d),
+				err: nil,
+			}
+		}
+		sums[i] = sum
+	}
+
+	return sums
+}
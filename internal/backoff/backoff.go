@@ -0,0 +1,51 @@
+package backoff
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Backoff doubles the wait after each retry up to limit, a limit of 0 doesn't cap it
+type Backoff struct {
+	current time.Duration
+	limit   time.Duration
+}
+
+func New(initial time.Duration, limit time.Duration) *Backoff {
+	return &Backoff{
+		current: initial,
+		limit:   limit,
+	}
+}
+
+// Next the wait before the next retry, between half and all of the current backoff so the clients which failed
+// together don't retry in lockstep
+func (b *Backoff) Next() time.Duration {
+	wait := b.current/2 + time.Duration(rand.Int63n(int64(b.current/2)+1))
+
+	b.current *= 2
+	if b.limit > 0 && b.current > b.limit {
+		b.current = b.limit
+	}
+
+	return wait
+}
+
+// Wait sleeps for Next, false without sleeping if the wait would end after the deadline of ctx or once ctx ends
+func (b *Backoff) Wait(ctx context.Context) bool {
+	wait := b.Next()
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+		return false
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path"
 	"runtime"
+	"strings"
 	"time"
 )
 
@@ -95,6 +96,40 @@ func Caller() string {
 	return fmt.Sprintf("%s:%d", file, line)
 }
 
+// CallerOutside the file:line of the first caller outside of the packages whose import path starts with one of the
+// prefixes, for the logs of a library which runs user code through its own frames, as a database driver
+func CallerOutside(prefixes ...string) string {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	for {
+		frame, more := frames.Next()
+
+		inside := strings.HasPrefix(frame.Function, "runtime.")
+		for _, prefix := range prefixes {
+			inside = inside || strings.HasPrefix(frame.Function, prefix)
+		}
+
+		if !inside {
+			file := frame.File
+			if ignoreDir != "" && strings.HasPrefix(file, ignoreDir+"/") {
+				file = file[len(ignoreDir)+1:]
+			}
+
+			return fmt.Sprintf("%s:%d", file, frame.Line)
+		}
+
+		if !more {
+			return "???:0"
+		}
+	}
+}
+
+// Log writes at level with the caller given, as found by CallerOutside
+func Log(level string, caller string, format string, args ...interface{}) {
+	fmt.Printf("[%s] %s | %s | %s\n", level, time.Now().UTC().Format(TimeLayout), caller, fmt.Sprintf(format, args...))
+}
+
 func Error(format string, args ...interface{}) {
 	log(Level_Error, format, args...)
 }
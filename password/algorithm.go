@@ -0,0 +1,172 @@
+package password
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrMismatch             = fmt.Errorf("password mismatch")
+	ErrUnsupportedAlgorithm = fmt.Errorf("unsupported hash algorithm")
+	ErrMalformedHash        = fmt.Errorf("malformed hash")
+)
+
+// Algorithm a password hashing scheme, Verify and Outdated read the parameters from the hash itself
+type Algorithm interface {
+	Name() string
+	// Match reports whether the hash was created by this scheme
+	Match(encrypted string) bool
+	Hash(pwd []byte) (string, error)
+	// Verify returns ErrMismatch or an error wrapping ErrMalformedHash when the password does not match
+	Verify(pwd []byte, encrypted string) error
+	// Outdated reports whether the hash parameters are weaker than the configured ones
+	Outdated(encrypted string) bool
+}
+
+// algorithms the schemes Verify dispatches to, the parameters here only matter for Outdated
+var algorithms = []Algorithm{
+	NewBcrypt(DefaultCost),
+	NewArgon2id(),
+	NewScrypt(),
+}
+
+func algorithmOf(encrypted string) (Algorithm, error) {
+	for _, algorithm := range algorithms {
+		if algorithm.Match(encrypted) {
+			return algorithm, nil
+		}
+	}
+
+	return nil, ErrUnsupportedAlgorithm
+}
+
+type Bcrypt struct {
+	cost int
+}
+
+func NewBcrypt(cost int) *Bcrypt {
+	return &Bcrypt{
+		cost: cost,
+	}
+}
+
+func (b *Bcrypt) Name() string {
+	return "bcrypt"
+}
+
+func (b *Bcrypt) Match(encrypted string) bool {
+	return strings.HasPrefix(encrypted, "$2a$") || strings.HasPrefix(encrypted, "$2b$") || strings.HasPrefix(encrypted, "$2y$")
+}
+
+func (b *Bcrypt) Hash(pwd []byte) (string, error) {
+	encrypted, err := bcrypt.GenerateFromPassword(pwd, b.cost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(encrypted), nil
+}
+
+func (b *Bcrypt) Verify(pwd []byte, encrypted string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(encrypted), pwd)
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrMismatch
+	}
+
+	return fmt.Errorf("%w: %s", ErrMalformedHash, err.Error())
+}
+
+func (b *Bcrypt) Outdated(encrypted string) bool {
+	cost, err := bcrypt.Cost([]byte(encrypted))
+	if err != nil {
+		return true
+	}
+
+	return cost < b.cost
+}
+
+// phc the PHC string format, $<id>$[v=<version>$]<params>$<salt>$<hash> with unpadded base64
+type phc struct {
+	id      string
+	version string
+	params  map[string]string
+	salt    []byte
+	hash    []byte
+}
+
+func (p phc) String() string {
+	params := make([]string, 0, len(p.params))
+	for _, key := range []string{"m", "t", "ln", "r", "p"} {
+		if v, ok := p.params[key]; ok {
+			params = append(params, key+"="+v)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("$" + p.id)
+	if p.version != "" {
+		b.WriteString("$v=" + p.version)
+	}
+	b.WriteString("$" + strings.Join(params, ","))
+	b.WriteString("$" + base64.RawStdEncoding.EncodeToString(p.salt))
+	b.WriteString("$" + base64.RawStdEncoding.EncodeToString(p.hash))
+
+	return b.String()
+}
+
+func parsePHC(encrypted string) (phc, error) {
+	parts := strings.Split(encrypted, "$")
+	if len(parts) < 5 || parts[0] != "" {
+		return phc{}, ErrMalformedHash
+	}
+
+	p := phc{
+		id:     parts[1],
+		params: map[string]string{},
+	}
+	parts = parts[2:]
+
+	if strings.HasPrefix(parts[0], "v=") {
+		p.version = strings.TrimPrefix(parts[0], "v=")
+		parts = parts[1:]
+	}
+	if len(parts) != 3 {
+		return phc{}, ErrMalformedHash
+	}
+
+	for _, param := range strings.Split(parts[0], ",") {
+		key, value, ok := strings.Cut(param, "=")
+		if !ok {
+			return phc{}, ErrMalformedHash
+		}
+		p.params[key] = value
+	}
+
+	var err error
+	if p.salt, err = base64.RawStdEncoding.DecodeString(parts[1]); err != nil {
+		return phc{}, fmt.Errorf("%w: %w", ErrMalformedHash, err)
+	}
+	if p.hash, err = base64.RawStdEncoding.DecodeString(parts[2]); err != nil {
+		return phc{}, fmt.Errorf("%w: %w", ErrMalformedHash, err)
+	}
+
+	return p, nil
+}
+
+func salt(length int) ([]byte, error) {
+	s := make([]byte, length)
+	if _, err := rand.Read(s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
@@ -0,0 +1,133 @@
+package password
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// the largest parameters accepted from a stored hash, a corrupt one would otherwise exhaust the memory or the CPU
+const (
+	// maxArgon2Memory 1 GiB in KiB
+	maxArgon2Memory  uint64 = 1 << 20
+	maxArgon2Time    uint64 = 64
+	maxArgon2Threads uint64 = 64
+)
+
+// Argon2id hashes look like $argon2id$v=19$m=19456,t=2,p=1$<salt>$<hash>
+type Argon2id struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+	saltLen int
+	keyLen  uint32
+}
+
+// NewArgon2id 19 MiB memory, 2 iterations and 1 thread, the OWASP recommended minimum
+func NewArgon2id() *Argon2id {
+	return &Argon2id{
+		memory:  19 * 1024,
+		time:    2,
+		threads: 1,
+		saltLen: 16,
+		keyLen:  32,
+	}
+}
+
+// Memory in KiB, hashes with more than 1 GiB, 64 iterations or 64 threads are refused by Verify
+func (a *Argon2id) Memory(memory uint32) *Argon2id {
+	a.memory = memory
+	return a
+}
+
+func (a *Argon2id) Time(time uint32) *Argon2id {
+	a.time = time
+	return a
+}
+
+func (a *Argon2id) Threads(threads uint8) *Argon2id {
+	a.threads = threads
+	return a
+}
+
+func (a *Argon2id) Name() string {
+	return "argon2id"
+}
+
+func (a *Argon2id) Match(encrypted string) bool {
+	return strings.HasPrefix(encrypted, "$argon2id$")
+}
+
+func (a *Argon2id) Hash(pwd []byte) (string, error) {
+	s, err := salt(a.saltLen)
+	if err != nil {
+		return "", err
+	}
+
+	return phc{
+		id:      "argon2id",
+		version: strconv.Itoa(argon2.Version),
+		params: map[string]string{
+			"m": strconv.FormatUint(uint64(a.memory), 10),
+			"t": strconv.FormatUint(uint64(a.time), 10),
+			"p": strconv.FormatUint(uint64(a.threads), 10),
+		},
+		salt: s,
+		hash: argon2.IDKey(pwd, s, a.time, a.memory, a.threads, a.keyLen),
+	}.String(), nil
+}
+
+func (a *Argon2id) Verify(pwd []byte, encrypted string) error {
+	p, params, err := a.parse(encrypted)
+	if err != nil {
+		return err
+	}
+
+	hash := argon2.IDKey(pwd, p.salt, params.time, params.memory, params.threads, uint32(len(p.hash)))
+	if subtle.ConstantTimeCompare(hash, p.hash) != 1 {
+		return ErrMismatch
+	}
+
+	return nil
+}
+
+func (a *Argon2id) Outdated(encrypted string) bool {
+	p, params, err := a.parse(encrypted)
+	if err != nil {
+		return true
+	}
+
+	return params.memory < a.memory || params.time < a.time || params.threads < a.threads || uint32(len(p.hash)) < a.keyLen
+}
+
+func (a *Argon2id) parse(encrypted string) (phc, Argon2id, error) {
+	p, err := parsePHC(encrypted)
+	if err != nil {
+		return phc{}, Argon2id{}, err
+	}
+	if p.id != "argon2id" {
+		return phc{}, Argon2id{}, ErrUnsupportedAlgorithm
+	}
+	if p.version != strconv.Itoa(argon2.Version) {
+		return phc{}, Argon2id{}, ErrUnsupportedAlgorithm
+	}
+
+	memory, err1 := strconv.ParseUint(p.params["m"], 10, 32)
+	time, err2 := strconv.ParseUint(p.params["t"], 10, 32)
+	threads, err3 := strconv.ParseUint(p.params["p"], 10, 8)
+	if err1 != nil || err2 != nil || err3 != nil || time == 0 || threads == 0 || len(p.hash) == 0 {
+		return phc{}, Argon2id{}, ErrMalformedHash
+	}
+	if memory > maxArgon2Memory || time > maxArgon2Time || threads > maxArgon2Threads {
+		return phc{}, Argon2id{}, fmt.Errorf("%w: m=%d,t=%d,p=%d is above the accepted maximum", ErrMalformedHash, memory, time, threads)
+	}
+
+	return p, Argon2id{
+		memory:  uint32(memory),
+		time:    uint32(time),
+		threads: uint8(threads),
+	}, nil
+}
@@ -0,0 +1,157 @@
+package password
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+const (
+	bloomMagic string = "PWBF"
+)
+
+// BloomFilter a compact BreachSource, it may report a password as breached which is not (at the configured rate)
+// but never misses one. Build it with NewBloomFilter and Add, then Save it and load it with LoadBloomFilter.
+type BloomFilter struct {
+	bits   []uint64
+	size   uint64
+	hashes uint32
+}
+
+// NewBloomFilter sized for n hashes with the false positive rate
+func NewBloomFilter(n uint64, rate float64) *BloomFilter {
+	size := uint64(math.Ceil(-float64(n) * math.Log(rate) / (math.Ln2 * math.Ln2)))
+	if size < 64 {
+		size = 64
+	}
+	hashes := uint32(math.Max(1, math.Round(float64(size)/float64(max(n, 1))*math.Ln2)))
+
+	return &BloomFilter{
+		bits:   make([]uint64, (size+63)/64),
+		size:   size,
+		hashes: hashes,
+	}
+}
+
+// positions double hashing over the SHA-1, which is already uniformly distributed
+func (b *BloomFilter) positions(hash string) ([]uint64, error) {
+	sum, err := hex.DecodeString(hash)
+	if err != nil || len(sum) != sha1HexLength/2 {
+		return nil, fmt.Errorf("%w: invalid sha1 %q", ErrBreachCheck, hash)
+	}
+
+	h1 := binary.BigEndian.Uint64(sum[0:8])
+	h2 := binary.BigEndian.Uint64(sum[8:16]) | 1
+
+	positions := make([]uint64, b.hashes)
+	for i := range positions {
+		positions[i] = (h1 + uint64(i)*h2) % b.size
+	}
+
+	return positions, nil
+}
+
+// Add hash is the hex SHA-1 of a breached password
+func (b *BloomFilter) Add(hash string) error {
+	positions, err := b.positions(hash)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range positions {
+		b.bits[p/64] |= 1 << (p % 64)
+	}
+
+	return nil
+}
+
+// AddFrom adds the hashes of "HASH:COUNT" lines
+func (b *BloomFilter) AddFrom(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) < sha1HexLength {
+			continue
+		}
+		if err := b.Add(line[:sha1HexLength]); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+func (b *BloomFilter) Breached(hash string) (bool, error) {
+	positions, err := b.positions(hash)
+	if err != nil {
+		return false, err
+	}
+
+	for _, p := range positions {
+		if b.bits[p/64]&(1<<(p%64)) == 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (b *BloomFilter) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(file)
+	w.WriteString(bloomMagic)
+	binary.Write(w, binary.BigEndian, b.size)
+	binary.Write(w, binary.BigEndian, b.hashes)
+	if err := binary.Write(w, binary.BigEndian, b.bits); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+func LoadBloomFilter(path string) (*BloomFilter, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	r := bufio.NewReader(file)
+
+	magic := make([]byte, len(bloomMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != bloomMagic {
+		return nil, fmt.Errorf("%w: %s is not a bloom filter", ErrBreachCheck, path)
+	}
+
+	b := &BloomFilter{}
+	if err := binary.Read(r, binary.BigEndian, &b.size); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(r, binary.BigEndian, &b.hashes); err != nil {
+		return nil, err
+	}
+	if b.size == 0 || b.hashes == 0 {
+		return nil, fmt.Errorf("%w: %s is not a bloom filter", ErrBreachCheck, path)
+	}
+
+	b.bits = make([]uint64, (b.size+63)/64)
+	if err := binary.Read(r, binary.BigEndian, b.bits); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
@@ -0,0 +1,175 @@
+package password
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrBreached    = fmt.Errorf("password has appeared in a data breach")
+	ErrBreachCheck = fmt.Errorf("breach check failed")
+)
+
+// BreachSource a local list of breached passwords, hash is the uppercase hex SHA-1 of the password
+type BreachSource interface {
+	Breached(hash string) (bool, error)
+}
+
+// Breached reports whether the password is in the source
+func Breached(source BreachSource, pwd string) (bool, error) {
+	sum := sha1.Sum([]byte(pwd))
+
+	return source.Breached(strings.ToUpper(hex.EncodeToString(sum[:])))
+}
+
+const (
+	sha1HexLength int = 40
+	prefixLength  int = 5
+)
+
+// RangeFunc returns the "SUFFIX:COUNT" lines of every hash starting with the 5 characters prefix,
+// the same k-anonymity format as the Pwned Passwords range API
+type RangeFunc func(prefix string) (io.ReadCloser, error)
+
+func (f RangeFunc) Breached(hash string) (bool, error) {
+	r, err := f(hash[:prefixLength])
+	if err != nil {
+		return false, err
+	}
+	defer r.Close()
+
+	suffix := hash[prefixLength:]
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		s, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if strings.EqualFold(s, suffix) {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+// RangeDir reads the range of a prefix from <dir>/<PREFIX>.txt, the layout of downloaded Pwned Passwords ranges
+func RangeDir(dir string) RangeFunc {
+	return func(prefix string) (io.ReadCloser, error) {
+		return os.Open(filepath.Join(dir, prefix+".txt"))
+	}
+}
+
+// HashFile a file of "HASH:COUNT" lines sorted by hash, as the downloadable Pwned Passwords SHA-1 list,
+// looked up by binary search without loading it into memory
+type HashFile struct {
+	file *os.File
+	size int64
+}
+
+func OpenHashFile(path string) (*HashFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &HashFile{
+		file: file,
+		size: info.Size(),
+	}, nil
+}
+
+func (f *HashFile) Close() error {
+	return f.file.Close()
+}
+
+func (f *HashFile) Breached(hash string) (bool, error) {
+	lo, hi := int64(0), f.size
+	for lo < hi {
+		mid := (lo + hi) / 2
+
+		start, end, line, err := f.lineAt(mid)
+		if err != nil {
+			return false, err
+		}
+		if line == nil || start >= hi {
+			hi = mid
+			continue
+		}
+		if len(line) < sha1HexLength {
+			return false, fmt.Errorf("%w: malformed line at offset %d", ErrBreachCheck, start)
+		}
+
+		switch strings.Compare(strings.ToUpper(string(line[:sha1HexLength])), hash) {
+		case 0:
+			return true, nil
+		case -1:
+			lo = end + 1
+		default:
+			hi = mid
+		}
+	}
+
+	return false, nil
+}
+
+// lineAt returns the first line starting at or after offset and the offset of its newline, line is nil at the end of the file
+func (f *HashFile) lineAt(offset int64) (int64, int64, []byte, error) {
+	start := offset
+	if offset > 0 {
+		// the line begins after the first newline from offset-1
+		next, err := f.indexNewline(offset - 1)
+		if err != nil || next < 0 {
+			return 0, 0, nil, err
+		}
+		start = next + 1
+	}
+
+	end, err := f.indexNewline(start)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if end < 0 {
+		end = f.size
+	}
+	if start >= end {
+		return start, end, nil, nil
+	}
+
+	line := make([]byte, end-start)
+	if _, err := f.file.ReadAt(line, start); err != nil && err != io.EOF {
+		return 0, 0, nil, err
+	}
+
+	return start, end, bytes.TrimRight(line, "\r"), nil
+}
+
+// indexNewline the offset of the first '\n' at or after offset, -1 if there is none
+func (f *HashFile) indexNewline(offset int64) (int64, error) {
+	buf := make([]byte, 128)
+	for offset < f.size {
+		n, err := f.file.ReadAt(buf, offset)
+		if i := bytes.IndexByte(buf[:n], '\n'); i >= 0 {
+			return offset + int64(i), nil
+		}
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		offset += int64(n)
+		if n == 0 {
+			break
+		}
+	}
+
+	return -1, nil
+}
@@ -0,0 +1,250 @@
+package password
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type OTPHash string
+
+const (
+	OTPHash_SHA1   OTPHash = "SHA1"
+	OTPHash_SHA256 OTPHash = "SHA256"
+	OTPHash_SHA512 OTPHash = "SHA512"
+)
+
+func (h OTPHash) new() func() hash.Hash {
+	switch h {
+	case OTPHash_SHA256:
+		return sha256.New
+	case OTPHash_SHA512:
+		return sha512.New
+	}
+
+	return sha1.New
+}
+
+const (
+	// maxOTPDigits the truncated code has 31 bits, so more digits would only pad it with zeros
+	maxOTPDigits int = 9
+)
+
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewOTPSecret 20 random bytes, the size RFC 4226 recommends
+func NewOTPSecret() ([]byte, error) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+// SecretFromBase32 decodes secrets as shown to users, spaces and lowercase letters are accepted
+func SecretFromBase32(s string) ([]byte, error) {
+	s = strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+	return base32NoPadding.DecodeString(strings.TrimRight(s, "="))
+}
+
+func SecretToBase32(secret []byte) string {
+	return base32NoPadding.EncodeToString(secret)
+}
+
+// hotp RFC 4226 dynamic truncation
+func hotp(secret []byte, counter uint64, digits int, h OTPHash) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+
+	mac := hmac.New(h.new(), secret)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, code%mod)
+}
+
+func equalCode(a string, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+func otpURI(kind string, issuer string, account string, secret []byte, h OTPHash, digits int, extra url.Values) string {
+	label := url.PathEscape(account)
+	if issuer != "" {
+		label = url.PathEscape(issuer) + ":" + label
+	}
+
+	values := url.Values{}
+	values.Set("secret", SecretToBase32(secret))
+	if issuer != "" {
+		values.Set("issuer", issuer)
+	}
+	values.Set("algorithm", string(h))
+	values.Set("digits", strconv.Itoa(digits))
+	for k, v := range extra {
+		values[k] = v
+	}
+
+	return "otpauth://" + kind + "/" + label + "?" + values.Encode()
+}
+
+// HOTP counter based one-time passwords, RFC 4226
+type HOTP struct {
+	secret    []byte
+	digits    int
+	hash      OTPHash
+	lookAhead uint64
+}
+
+func NewHOTP(secret []byte) *HOTP {
+	return &HOTP{
+		secret:    secret,
+		digits:    6,
+		hash:      OTPHash_SHA1,
+		lookAhead: 0,
+	}
+}
+
+// Digits between 1 and 9, other values are ignored
+func (h *HOTP) Digits(digits int) *HOTP {
+	if digits >= 1 && digits <= maxOTPDigits {
+		h.digits = digits
+	}
+	return h
+}
+
+func (h *HOTP) Hash(hash OTPHash) *HOTP {
+	h.hash = hash
+	return h
+}
+
+// LookAhead the number of counters after the expected one which are accepted too, for unused codes on the client
+func (h *HOTP) LookAhead(window uint64) *HOTP {
+	h.lookAhead = window
+	return h
+}
+
+func (h *HOTP) Generate(counter uint64) string {
+	return hotp(h.secret, counter, h.digits, h.hash)
+}
+
+// Verify returns the counter to store for the next verification if the code is valid
+func (h *HOTP) Verify(code string, counter uint64) (next uint64, ok bool) {
+	last := counter + h.lookAhead
+	if last < counter {
+		last = math.MaxUint64
+	}
+
+	for c := counter; ; c++ {
+		if equalCode(h.Generate(c), code) {
+			return c + 1, true
+		}
+		if c == last {
+			break
+		}
+	}
+
+	return counter, false
+}
+
+func (h *HOTP) URI(issuer string, account string, counter uint64) string {
+	return otpURI("hotp", issuer, account, h.secret, h.hash, h.digits, url.Values{
+		"counter": {strconv.FormatUint(counter, 10)},
+	})
+}
+
+// TOTP time based one-time passwords, RFC 6238
+type TOTP struct {
+	secret []byte
+	digits int
+	hash   OTPHash
+	period time.Duration
+	skew   int
+}
+
+func NewTOTP(secret []byte) *TOTP {
+	return &TOTP{
+		secret: secret,
+		digits: 6,
+		hash:   OTPHash_SHA1,
+		period: 30 * time.Second,
+		skew:   1,
+	}
+}
+
+// Digits between 1 and 9, other values are ignored
+func (t *TOTP) Digits(digits int) *TOTP {
+	if digits >= 1 && digits <= maxOTPDigits {
+		t.digits = digits
+	}
+	return t
+}
+
+func (t *TOTP) Hash(hash OTPHash) *TOTP {
+	t.hash = hash
+	return t
+}
+
+// Period a whole number of seconds, periods under a second are ignored
+func (t *TOTP) Period(period time.Duration) *TOTP {
+	if period >= time.Second {
+		t.period = period
+	}
+	return t
+}
+
+// Skew the number of periods before and after the current one which are accepted for clock drift
+func (t *TOTP) Skew(periods int) *TOTP {
+	t.skew = periods
+	return t
+}
+
+func (t *TOTP) step(at time.Time) int64 {
+	return at.Unix() / int64(t.period/time.Second)
+}
+
+func (t *TOTP) Generate(at time.Time) string {
+	return hotp(t.secret, uint64(t.step(at)), t.digits, t.hash)
+}
+
+// Verify returns the matched time step, store it and refuse codes of steps not after it to prevent replays
+func (t *TOTP) Verify(code string, at time.Time) (step int64, ok bool) {
+	current := t.step(at)
+	for i := -t.skew; i <= t.skew; i++ {
+		s := current + int64(i)
+		if s < 0 {
+			continue
+		}
+		if equalCode(hotp(t.secret, uint64(s), t.digits, t.hash), code) {
+			return s, true
+		}
+	}
+
+	return 0, false
+}
+
+func (t *TOTP) URI(issuer string, account string) string {
+	return otpURI("totp", issuer, account, t.secret, t.hash, t.digits, url.Values{
+		"period": {strconv.Itoa(int(t.period / time.Second))},
+	})
+}
@@ -1,18 +1,219 @@
 package password
 
 import (
+	"fmt"
+	"sync"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
-func Encrypt(pwd string) (string, error) {
-	encrypted, err := bcrypt.GenerateFromPassword([]byte(pwd), bcrypt.DefaultCost)
+const (
+	DefaultCost int = bcrypt.DefaultCost
+)
+
+var (
+	ErrInvalidCost = fmt.Errorf("invalid cost")
+)
+
+// Hasher hashes with one algorithm and verifies hashes of every supported algorithm
+type Hasher struct {
+	algorithm    Algorithm
+	longPassword LongPassword
+
+	pepperID string
+	peppers  map[string][]byte
+	// pepperErr of an invalid key id given to Pepper or RetiredPepper, returned by Encrypt and Verify
+	pepperErr error
+
+	dummyOnce sync.Once
+	dummy     string
+	dummyErr  error
+}
+
+// NewHasher a bcrypt hasher
+func NewHasher(cost int) (*Hasher, error) {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("%w: %d, must be between %d and %d", ErrInvalidCost, cost, bcrypt.MinCost, bcrypt.MaxCost)
+	}
+
+	return NewHasherWith(NewBcrypt(cost)), nil
+}
+
+func NewHasherWith(algorithm Algorithm) *Hasher {
+	return &Hasher{
+		algorithm:    algorithm,
+		longPassword: LongPassword_Default,
+
+		pepperID:  "",
+		peppers:   map[string][]byte{},
+		pepperErr: nil,
+	}
+}
+
+func (h *Hasher) LongPassword(mode LongPassword) *Hasher {
+	h.longPassword = mode
+	return h
+}
+
+// Pepper new hashes are computed over the HMAC-SHA256 of the password keyed by the pepper,
+// the key id is stored in the hash so the pepper can be rotated, it must be non-empty and without '$'
+func (h *Hasher) Pepper(keyID string, pepper []byte) *Hasher {
+	if err := checkPepperID(keyID); err != nil {
+		h.pepperErr = err
+		return h
+	}
+
+	h.pepperID = keyID
+	h.peppers[keyID] = pepper
+	return h
+}
+
+// RetiredPepper a pepper only used to verify existing hashes, they are rehashed with the current pepper
+func (h *Hasher) RetiredPepper(keyID string, pepper []byte) *Hasher {
+	if err := checkPepperID(keyID); err != nil {
+		h.pepperErr = err
+		return h
+	}
+
+	h.peppers[keyID] = pepper
+	return h
+}
+
+var defaultHasher = NewHasherWith(NewBcrypt(DefaultCost))
+
+func (h *Hasher) Encrypt(pwd string) (string, error) {
+	if h.pepperErr != nil {
+		return "", h.pepperErr
+	}
+
+	input := []byte(pwd)
+	prefix := ""
+
+	switch {
+	case h.pepperID != "":
+		input = preHash(input, h.peppers[h.pepperID])
+		prefix = prefix_HMACSHA256 + h.pepperID
+	case h.longPassword == LongPassword_PreHash:
+		input = preHash(input, nil)
+		prefix = prefix_SHA256
+	case h.longPassword == LongPassword_Reject && tooLong(h.algorithm, input):
+		return "", ErrTooLong
+	}
+
+	encrypted, err := h.algorithm.Hash(input)
 	if err != nil {
 		return "", err
 	}
 
-	return string(encrypted), nil
+	return prefix + encrypted, nil
+}
+
+// Verify dispatches on the hash prefix, so hashes of different algorithms can coexist during a migration.
+// ErrMismatch, ErrMalformedHash, ErrUnsupportedAlgorithm or ErrUnknownPepper is returned on failure,
+// ErrInvalidPepperID when the hasher was given an invalid pepper key id.
+func (h *Hasher) Verify(pwd string, encrypted string) error {
+	if h.pepperErr != nil {
+		return h.pepperErr
+	}
+
+	scheme, keyID, inner, err := unwrap(encrypted)
+	if err != nil {
+		return err
+	}
+
+	algorithm, err := algorithmOf(inner)
+	if err != nil {
+		return err
+	}
+
+	input := []byte(pwd)
+	switch scheme {
+	case prefix_HMACSHA256:
+		pepper, ok := h.peppers[keyID]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownPepper, keyID)
+		}
+		input = preHash(input, pepper)
+	case prefix_SHA256:
+		input = preHash(input, nil)
+	default:
+		if h.longPassword == LongPassword_Reject && tooLong(algorithm, input) {
+			return ErrTooLong
+		}
+	}
+
+	return algorithm.Verify(input, inner)
+}
+
+// VerifyDummy verifies the password against a hash made with the hasher's own settings and always returns ErrMismatch,
+// call it for unknown users so the response time does not reveal whether the user exists.
+// The dummy hash is created by the first call.
+func (h *Hasher) VerifyDummy(pwd string) error {
+	h.dummyOnce.Do(func() {
+		h.dummy, h.dummyErr = h.Encrypt("dummy password for unknown users")
+	})
+	if h.dummyErr != nil {
+		return h.dummyErr
+	}
+
+	// only the time spent matters, the result is always a mismatch
+	_ = h.Verify(pwd, h.dummy)
+
+	return ErrMismatch
+}
+
+// NeedsRehash reports whether the hash was created by another algorithm, with weaker parameters or without the
+// current pepper or pre-hash
+func (h *Hasher) NeedsRehash(encrypted string) bool {
+	scheme, keyID, inner, err := unwrap(encrypted)
+	if err != nil {
+		return true
+	}
+
+	switch {
+	case h.pepperID != "":
+		if scheme != prefix_HMACSHA256 || keyID != h.pepperID {
+			return true
+		}
+	case h.longPassword == LongPassword_PreHash:
+		if scheme != prefix_SHA256 {
+			return true
+		}
+	}
+
+	if !h.algorithm.Match(inner) {
+		return true
+	}
+
+	return h.algorithm.Outdated(inner)
+}
+
+// VerifyAndUpgrade verifies the password and rehashes it if the hash is outdated, store newHash when needsRehash is true
+func (h *Hasher) VerifyAndUpgrade(pwd string, encrypted string) (newHash string, needsRehash bool, err error) {
+	if err := h.Verify(pwd, encrypted); err != nil {
+		return "", false, err
+	}
+
+	if !h.NeedsRehash(encrypted) {
+		return "", false, nil
+	}
+
+	newHash, err = h.Encrypt(pwd)
+	if err != nil {
+		return "", false, err
+	}
+
+	return newHash, true, nil
+}
+
+func Encrypt(pwd string) (string, error) {
+	return defaultHasher.Encrypt(pwd)
 }
 
 func Verify(pwd string, encrypted string) error {
-	return bcrypt.CompareHashAndPassword([]byte(encrypted), []byte(pwd))
+	return defaultHasher.Verify(pwd, encrypted)
+}
+
+func VerifyDummy(pwd string) error {
+	return defaultHasher.VerifyDummy(pwd)
 }
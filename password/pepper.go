@@ -0,0 +1,93 @@
+package password
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrUnknownPepper   = fmt.Errorf("unknown pepper key id")
+	ErrInvalidPepperID = fmt.Errorf("invalid pepper key id, it must be non-empty and without '$'")
+)
+
+// LongPassword how passwords longer than the algorithm accepts (72 bytes for bcrypt) are handled
+type LongPassword int
+
+const (
+	// LongPassword_Default bcrypt refuses to hash them, existing hashes still verify the first 72 bytes only
+	LongPassword_Default LongPassword = iota
+	// LongPassword_Reject ErrTooLong is returned by both Encrypt and Verify
+	LongPassword_Reject
+	// LongPassword_PreHash new hashes are computed over the SHA-256 of the password
+	LongPassword_PreHash
+)
+
+// pre-hashed hashes wrap the hash of the algorithm,
+// $sha256$2a$10$... or $hmac-sha256$k=<key id>$2a$10$...
+const (
+	prefix_SHA256     string = "$sha256"
+	prefix_HMACSHA256 string = "$hmac-sha256$k="
+)
+
+// limited algorithms which only use a prefix of the password
+type limited interface {
+	maxLength() int
+}
+
+func (b *Bcrypt) maxLength() int {
+	return 72
+}
+
+// checkPepperID the key id ends at the first '$' of a hash and an empty one would disable the pepper
+func checkPepperID(keyID string) error {
+	if keyID == "" || strings.ContainsRune(keyID, '$') {
+		return fmt.Errorf("%w: %q", ErrInvalidPepperID, keyID)
+	}
+
+	return nil
+}
+
+// unwrap splits a hash into its pre-hash scheme, pepper key id and the hash of the algorithm
+func unwrap(encrypted string) (scheme string, keyID string, inner string, err error) {
+	if rest, ok := strings.CutPrefix(encrypted, prefix_HMACSHA256); ok {
+		i := strings.IndexByte(rest, '$')
+		if i <= 0 {
+			return "", "", "", ErrMalformedHash
+		}
+
+		return prefix_HMACSHA256, rest[:i], rest[i:], nil
+	}
+
+	if rest, ok := strings.CutPrefix(encrypted, prefix_SHA256+"$"); ok {
+		return prefix_SHA256, "", "$" + rest, nil
+	}
+
+	return "", "", encrypted, nil
+}
+
+// preHash the base64 encoding keeps NUL bytes out of bcrypt and fits in 44 bytes
+func preHash(pwd []byte, pepper []byte) []byte {
+	var sum []byte
+	if pepper != nil {
+		mac := hmac.New(sha256.New, pepper)
+		mac.Write(pwd)
+		sum = mac.Sum(nil)
+	} else {
+		s := sha256.Sum256(pwd)
+		sum = s[:]
+	}
+
+	encoded := make([]byte, base64.StdEncoding.EncodedLen(len(sum)))
+	base64.StdEncoding.Encode(encoded, sum)
+
+	return encoded
+}
+
+func tooLong(algorithm Algorithm, pwd []byte) bool {
+	l, ok := algorithm.(limited)
+
+	return ok && len(pwd) > l.maxLength()
+}
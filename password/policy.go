@@ -0,0 +1,280 @@
+package password
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// commonList the most used passwords of breach corpora, only those of at least 8 characters as shorter ones fail
+// the default minimum length anyway
+//
+//go:embed common.txt
+var commonList string
+
+var common map[string]struct{}
+
+func init() {
+	common = map[string]struct{}{}
+	for _, line := range strings.Split(commonList, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			common[strings.ToLower(line)] = struct{}{}
+		}
+	}
+}
+
+var (
+	ErrTooShort           = fmt.Errorf("password is too short")
+	ErrTooLong            = fmt.Errorf("password is too long")
+	ErrMissingClass       = fmt.Errorf("password is missing a character class")
+	ErrForbiddenSubstring = fmt.Errorf("password contains a forbidden substring")
+	ErrRepeated           = fmt.Errorf("password repeats a character too many times")
+	ErrSequence           = fmt.Errorf("password contains a too long sequence")
+	ErrCommon             = fmt.Errorf("password is too common")
+)
+
+type Class int
+
+const (
+	Class_Lower Class = iota
+	Class_Upper
+	Class_Digit
+	Class_Symbol
+)
+
+func (c Class) String() string {
+	switch c {
+	case Class_Lower:
+		return "lowercase letter"
+	case Class_Upper:
+		return "uppercase letter"
+	case Class_Digit:
+		return "digit"
+	case Class_Symbol:
+		return "symbol"
+	}
+
+	return "unknown"
+}
+
+func (c Class) match(r rune) bool {
+	switch c {
+	case Class_Lower:
+		return unicode.IsLower(r)
+	case Class_Upper:
+		return unicode.IsUpper(r)
+	case Class_Digit:
+		return unicode.IsDigit(r)
+	case Class_Symbol:
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r)
+	}
+
+	return false
+}
+
+// Context user information the password must not contain
+type Context struct {
+	Username string
+	Email    string
+	Extra    []string
+}
+
+func (c Context) forbidden() []string {
+	forbidden := []string{c.Username, c.Email}
+	if local, _, ok := strings.Cut(c.Email, "@"); ok {
+		forbidden = append(forbidden, local)
+	}
+
+	return append(forbidden, c.Extra...)
+}
+
+const (
+	// minForbiddenLength shorter forbidden substrings are ignored, they would reject too many passwords
+	minForbiddenLength int = 3
+)
+
+type Policy struct {
+	minLength   int
+	maxLength   int
+	classes     []Class
+	maxRepeat   int
+	maxSequence int
+	blocklist   bool
+	breach      BreachSource
+}
+
+// NewPolicy lengths between 8 and 64 and no common passwords, as NIST SP 800-63B suggests
+func NewPolicy() *Policy {
+	return &Policy{
+		minLength:   8,
+		maxLength:   64,
+		classes:     []Class{},
+		maxRepeat:   0,
+		maxSequence: 0,
+		blocklist:   true,
+		breach:      nil,
+	}
+}
+
+func (p *Policy) MinLength(length int) *Policy {
+	p.minLength = length
+	return p
+}
+
+// MaxLength 0 means unlimited
+func (p *Policy) MaxLength(length int) *Policy {
+	p.maxLength = length
+	return p
+}
+
+func (p *Policy) Require(classes ...Class) *Policy {
+	p.classes = append(p.classes, classes...)
+	return p
+}
+
+// MaxRepeat the maximum times a character may repeat in a row, 0 means unlimited
+func (p *Policy) MaxRepeat(count int) *Policy {
+	p.maxRepeat = count
+	return p
+}
+
+// MaxSequence the maximum length of an ascending or descending run like "abcd" or "4321", 0 means unlimited
+func (p *Policy) MaxSequence(length int) *Policy {
+	p.maxSequence = length
+	return p
+}
+
+func (p *Policy) Blocklist(enable bool) *Policy {
+	p.blocklist = enable
+	return p
+}
+
+// Breach rejects passwords found in the source, a failing source is reported as ErrBreachCheck
+func (p *Policy) Breach(source BreachSource) *Policy {
+	p.breach = source
+	return p
+}
+
+// Validate returns nil or the joined errors of all violations, each of them wraps one of the Err constants
+func (p *Policy) Validate(pwd string, ctx Context) error {
+	errs := []error{}
+
+	length := utf8.RuneCountInString(pwd)
+	if length < p.minLength {
+		errs = append(errs, fmt.Errorf("%w: at least %d characters", ErrTooShort, p.minLength))
+	}
+	if p.maxLength > 0 && length > p.maxLength {
+		errs = append(errs, fmt.Errorf("%w: at most %d characters", ErrTooLong, p.maxLength))
+	}
+
+	for _, class := range p.classes {
+		if strings.IndexFunc(pwd, class.match) < 0 {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrMissingClass, class))
+		}
+	}
+
+	lower := strings.ToLower(pwd)
+	for _, s := range ctx.forbidden() {
+		if utf8.RuneCountInString(s) < minForbiddenLength {
+			continue
+		}
+		if strings.Contains(lower, strings.ToLower(s)) {
+			errs = append(errs, fmt.Errorf("%w: %q", ErrForbiddenSubstring, s))
+		}
+	}
+
+	if p.maxRepeat > 0 {
+		if run := longestRun(pwd, 0); run > p.maxRepeat {
+			errs = append(errs, fmt.Errorf("%w: %d in a row, at most %d", ErrRepeated, run, p.maxRepeat))
+		}
+	}
+
+	if p.maxSequence > 0 {
+		run := max(longestRun(lower, 1), longestRun(lower, -1))
+		if run > p.maxSequence {
+			errs = append(errs, fmt.Errorf("%w: %d characters, at most %d", ErrSequence, run, p.maxSequence))
+		}
+	}
+
+	if p.blocklist {
+		if _, ok := common[lower]; ok {
+			errs = append(errs, ErrCommon)
+		}
+	}
+
+	if p.breach != nil {
+		breached, err := Breached(p.breach, pwd)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: %w", ErrBreachCheck, err))
+		} else if breached {
+			errs = append(errs, ErrBreached)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Encrypt validates the password before hashing it
+func (p *Policy) Encrypt(pwd string, ctx Context) (string, error) {
+	if err := p.Validate(pwd, ctx); err != nil {
+		return "", err
+	}
+
+	return Encrypt(pwd)
+}
+
+// longestRun the longest run where each rune is the previous one plus step
+func longestRun(s string, step rune) int {
+	longest, run := 0, 0
+	prev := rune(-1)
+	for _, r := range s {
+		if prev >= 0 && r == prev+step {
+			run++
+		} else {
+			run = 1
+		}
+		longest = max(longest, run)
+		prev = r
+	}
+
+	return longest
+}
+
+var charsets = map[Class]string{
+	Class_Lower:  "abcdefghijklmnopqrstuvwxyz",
+	Class_Upper:  "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	Class_Digit:  "0123456789",
+	Class_Symbol: "!@#$%^&*()-_=+[]{};:,.?/",
+}
+
+const (
+	generateMaxAttempts int = 100
+)
+
+var (
+	ErrGenerate = fmt.Errorf("can't generate a password for the policy")
+)
+
+// Generate a random password of length characters from all classes which passes Validate
+func (p *Policy) Generate(length int) (string, error) {
+	charset := ""
+	for _, class := range []Class{Class_Lower, Class_Upper, Class_Digit, Class_Symbol} {
+		charset += charsets[class]
+	}
+
+	for i := 0; i < generateMaxAttempts; i++ {
+		pwd, err := randomString(charset, length)
+		if err != nil {
+			return "", err
+		}
+
+		if p.Validate(pwd, Context{}) == nil {
+			return pwd, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: length %d", ErrGenerate, length)
+}
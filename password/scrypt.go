@@ -0,0 +1,129 @@
+package password
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// the largest parameters accepted from a stored hash, scrypt needs 128 * r * N bytes of memory
+const (
+	// maxScryptMemory 1 GiB
+	maxScryptMemory int = 1 << 30
+	maxScryptP      int = 64
+)
+
+// Scrypt hashes look like $scrypt$ln=17,r=8,p=1$<salt>$<hash>, N is 2^ln
+type Scrypt struct {
+	ln      int
+	r       int
+	p       int
+	saltLen int
+	keyLen  int
+}
+
+// NewScrypt N=2^17, r=8 and p=1, the OWASP recommended minimum
+func NewScrypt() *Scrypt {
+	return &Scrypt{
+		ln:      17,
+		r:       8,
+		p:       1,
+		saltLen: 16,
+		keyLen:  32,
+	}
+}
+
+// Cost N is 2^ln, hashes needing more than 1 GiB or with p above 64 are refused by Verify
+func (s *Scrypt) Cost(ln int, r int, p int) *Scrypt {
+	s.ln = ln
+	s.r = r
+	s.p = p
+	return s
+}
+
+func (s *Scrypt) Name() string {
+	return "scrypt"
+}
+
+func (s *Scrypt) Match(encrypted string) bool {
+	return strings.HasPrefix(encrypted, "$scrypt$")
+}
+
+func (s *Scrypt) Hash(pwd []byte) (string, error) {
+	sa, err := salt(s.saltLen)
+	if err != nil {
+		return "", err
+	}
+
+	hash, err := scrypt.Key(pwd, sa, 1<<s.ln, s.r, s.p, s.keyLen)
+	if err != nil {
+		return "", err
+	}
+
+	return phc{
+		id: "scrypt",
+		params: map[string]string{
+			"ln": strconv.Itoa(s.ln),
+			"r":  strconv.Itoa(s.r),
+			"p":  strconv.Itoa(s.p),
+		},
+		salt: sa,
+		hash: hash,
+	}.String(), nil
+}
+
+func (s *Scrypt) Verify(pwd []byte, encrypted string) error {
+	p, params, err := s.parse(encrypted)
+	if err != nil {
+		return err
+	}
+
+	// the parameters come from the stored hash, scrypt refuses the ones too large for it
+	hash, err := scrypt.Key(pwd, p.salt, 1<<params.ln, params.r, params.p, len(p.hash))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMalformedHash, err)
+	}
+	if subtle.ConstantTimeCompare(hash, p.hash) != 1 {
+		return ErrMismatch
+	}
+
+	return nil
+}
+
+func (s *Scrypt) Outdated(encrypted string) bool {
+	p, params, err := s.parse(encrypted)
+	if err != nil {
+		return true
+	}
+
+	return params.ln < s.ln || params.r < s.r || params.p < s.p || len(p.hash) < s.keyLen
+}
+
+func (s *Scrypt) parse(encrypted string) (phc, Scrypt, error) {
+	p, err := parsePHC(encrypted)
+	if err != nil {
+		return phc{}, Scrypt{}, err
+	}
+	if p.id != "scrypt" {
+		return phc{}, Scrypt{}, ErrUnsupportedAlgorithm
+	}
+
+	ln, err1 := strconv.Atoi(p.params["ln"])
+	r, err2 := strconv.Atoi(p.params["r"])
+	pp, err3 := strconv.Atoi(p.params["p"])
+	if err1 != nil || err2 != nil || err3 != nil || ln <= 0 || ln >= 32 || r <= 0 || pp <= 0 || len(p.hash) == 0 {
+		return phc{}, Scrypt{}, ErrMalformedHash
+	}
+	if r > (maxScryptMemory/128)>>ln || pp > maxScryptP {
+		return phc{}, Scrypt{}, fmt.Errorf("%w: ln=%d,r=%d,p=%d is above the accepted maximum", ErrMalformedHash, ln, r, pp)
+	}
+
+	return p, Scrypt{
+		ln: ln,
+		r:  r,
+		p:  pp,
+	}, nil
+}
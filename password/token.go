@@ -0,0 +1,85 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrInvalidToken = fmt.Errorf("invalid token")
+)
+
+const (
+	base62 string = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+	apiKeyLength   int = 30
+	checksumLength int = 6
+)
+
+// RandomToken n random bytes encoded URL-safe, for reset links and session ids
+func RandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// NewAPIKey keys look like <prefix>_<30 random base62 characters><6 characters CRC32 checksum>,
+// the checksum lets CheckAPIKey reject mistyped keys without a database lookup
+func NewAPIKey(prefix string) (string, error) {
+	body, err := randomString(base62, apiKeyLength)
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + "_" + body + checksum(body), nil
+}
+
+func CheckAPIKey(key string, prefix string) error {
+	body, ok := strings.CutPrefix(key, prefix+"_")
+	if !ok || len(body) != apiKeyLength+checksumLength {
+		return ErrInvalidToken
+	}
+
+	if checksum(body[:apiKeyLength]) != body[apiKeyLength:] {
+		return ErrInvalidToken
+	}
+
+	return nil
+}
+
+// HashToken tokens are random enough to be stored as a plain SHA-256
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func checksum(s string) string {
+	sum := crc32.ChecksumIEEE([]byte(s))
+	encoded := big.NewInt(int64(sum)).Text(62)
+
+	return strings.Repeat("0", checksumLength-len(encoded)) + encoded
+}
+
+func randomString(charset string, length int) (string, error) {
+	max := big.NewInt(int64(len(charset)))
+
+	b := make([]byte, length)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = charset[n.Int64()]
+	}
+
+	return string(b), nil
+}